@@ -0,0 +1,1207 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nickwells/check.mod/v2/check"
+	"github.com/nickwells/english.mod/english"
+	"github.com/nickwells/filecheck.mod/filecheck"
+	"github.com/nickwells/param.mod/v6/param"
+	"github.com/nickwells/param.mod/v6/psetter"
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+const (
+	paramNameWordFile             = "word-file"
+	paramNameAlgo                 = "algo"
+	paramNameToLower              = "to-lower"
+	paramNamePageSize             = "page-size"
+	paramNameMinStrLen            = "min-str-len"
+	paramNameMaxLength            = "max-length"
+	paramNameMaxResults           = "max-results"
+	paramNameTransliterate        = "transliterate"
+	paramNameStatusFile           = "status-file"
+	paramNameCheckSymmetry        = "check-symmetry"
+	paramNameSymmetrise           = "symmetrise"
+	paramNamePartial              = "partial"
+	paramNameEmptyCell            = "empty-cell"
+	paramNameRequestFile          = "request-file"
+	paramNameFreqColumn           = "frequency-column"
+	paramNamePreferFreq           = "prefer-frequent"
+	paramNameCompact              = "compact"
+	paramNameExplainAlgo          = "explain-algo-syntax"
+	paramNameUseTrie              = "use-trie"
+	paramNameTopK                 = "top-k"
+	paramNameGoldFile             = "gold-file"
+	paramNameROC                  = "roc"
+	paramNameSkipEmpty            = "skip-empty-targets"
+	paramNameRequireMatch         = "require-matches"
+	paramNameFailOnNoMatch        = "fail-on-no-match"
+	paramNameSortAlgos            = "sort-algos"
+	paramNameTokenise             = "tokenise"
+	paramNameTokenRegex           = "token-regex"
+	paramNameFlatSorted           = "flat-sorted"
+	paramNameEnsemble             = "ensemble"
+	paramNameDedupAlgos           = "dedup-algos"
+	paramNameRawBest              = "raw-best"
+	paramNameDetectEquiv          = "detect-equivalent"
+	paramNameMinDistance          = "min-distance"
+	paramNameMaxDistance          = "max-distance"
+	paramNameWorst                = "worst"
+	paramNameGroupByDist          = "group-by-distance"
+	paramNameGroupBy              = "group-by"
+	paramNameFullGrid             = "full-grid"
+	paramNameMeasureMemory        = "measure-memory"
+	paramNameHideCommon           = "hide-common-columns"
+	paramNameTargetCharset        = "target-charset"
+	paramNameStrict               = "strict"
+	paramNameAuditFile            = "audit-file"
+	paramNameMaxConcurrent        = "max-concurrent-algos"
+	paramNameGraphemeClust        = "grapheme-clusters"
+	paramNameWithConfidence       = "with-confidence"
+	paramNameServe                = "serve"
+	paramNameWhyTarget            = "why-target"
+	paramNameWhyWord              = "why-word"
+	paramNameParquetFile          = "parquet-file"
+	paramNamePerAlgoBudget        = "per-algo-budget"
+	paramNamePivotCSVFile         = "pivot-csv-file"
+	paramNameInvert               = "invert"
+	paramNameInvertAgg            = "invert-aggregate"
+	paramNameOutputFormat         = "output-format"
+	paramNameNoHeader             = "no-header"
+	paramNameShowSimilarity       = "show-similarity"
+	paramNameInteractive          = "interactive"
+	paramNameWarnDefaults         = "warn-defaults"
+	paramNameDefaultToLower       = "default-to-lower"
+	paramNameDefaultStripRunes    = "default-strip-runes"
+	paramNameAlgoFile             = "algo-file"
+	paramNameColumns              = "columns"
+	paramNameTranspose            = "transpose"
+	paramNameJobs                 = "jobs"
+	paramNameMaxConcurrentTargets = "max-concurrent-targets"
+	paramNameQueryFile            = "query-file"
+	paramNameUniqueQueries        = "unique-queries"
+	paramNameDedupPop             = "dedup-population"
+	paramNamePopMinLen            = "pop-min-len"
+	paramNamePopMaxLen            = "pop-max-len"
+	paramNameTrimPop              = "trim-population"
+	paramNameSkipComments         = "skip-comments"
+	paramNameCommentPrefix        = "comment-prefix"
+	paramNameMaxLineLen           = "max-line-len"
+	paramNameOutputFile           = "output-file"
+	paramNameShowTiming           = "show-timing"
+	paramNameSummary              = "summary"
+	paramNameBenchmark            = "benchmark"
+	paramNameBenchmarkReps        = "benchmark-reps"
+	paramNameProgress             = "progress"
+)
+
+func addParams(prog *Prog) param.PSetOptFunc {
+	return func(ps *param.PSet) error {
+		ps.Add(paramNameWordFile,
+			psetter.PathnameListAppender{
+				Value:       &prog.wordFiles,
+				Expectation: filecheck.FileNonEmpty(),
+			},
+			"the name of a file containing a population of words to"+
+				" be searched. Repeat to search the concatenation of"+
+				" several files, for instance to keep nouns, verbs and"+
+				" names in separate dictionaries; entries are appended"+
+				" in the order the files were given, duplicates and"+
+				" all. A file with a .gz suffix, or whose content"+
+				" starts with the gzip magic bytes, is transparently"+
+				" decompressed. Not needed if "+paramNameRequestFile+
+				" is given",
+		)
+
+		ps.Add(paramNameDedupPop,
+			psetter.Bool{Value: &prog.dedupPopulation},
+			"remove duplicate entries from the population after it has"+
+				" been read, keeping the first occurrence of each,"+
+				" before the finders run. Left unset, a population"+
+				" containing repeated dictionary lines does needless"+
+				" duplicate work and can make the same word appear"+
+				" several times in a target's results",
+		)
+
+		ps.Add(paramNamePopMinLen,
+			psetter.Int[int]{
+				Value:  &prog.popMinLen,
+				Checks: []check.ValCk[int]{check.ValGT(0)},
+			},
+			"drop population entries with fewer runes than this"+
+				" before the finders run. Comparing against very"+
+				" short words is often pointless noise for some"+
+				" algorithms. Unset by default, leaving the"+
+				" population unchanged",
+		)
+
+		ps.Add(paramNamePopMaxLen,
+			psetter.Int[int]{
+				Value:  &prog.popMaxLen,
+				Checks: []check.ValCk[int]{check.ValGT(0)},
+			},
+			"drop population entries with more runes than this"+
+				" before the finders run, see "+paramNamePopMinLen,
+		)
+
+		ps.Add(paramNameTrimPop,
+			psetter.Bool{Value: &prog.trimPopulation},
+			"trim leading and trailing whitespace from each population"+
+				" entry before the finders run, dropping any that"+
+				" become empty. Word files exported from spreadsheets"+
+				" often carry trailing spaces or a stray carriage"+
+				" return that would otherwise show up in the quoted"+
+				" result values and throw off distances. Left unset so"+
+				" that genuine leading-space tokens aren't surprised",
+		)
+
+		ps.Add(paramNameSkipComments,
+			psetter.Bool{Value: &prog.skipComments},
+			"skip blank lines and lines whose first non-space"+
+				" character starts with "+paramNameCommentPrefix+
+				" when reading "+paramNameWordFile+
+				", so annotated dictionaries don't pollute the"+
+				" population",
+		)
+
+		ps.Add(paramNameCommentPrefix,
+			psetter.String[string]{Value: &prog.commentPrefix},
+			"the prefix that marks a comment line, only used if "+
+				paramNameSkipComments+" is set",
+		)
+
+		ps.Add(paramNameMaxLineLen,
+			psetter.Int[int]{
+				Value:  &prog.maxLineLen,
+				Checks: []check.ValCk[int]{check.ValGT(0)},
+			},
+			fmt.Sprintf(
+				"the maximum length, in bytes, of a single line when"+
+					" reading "+paramNameWordFile+". The default (%d)"+
+					" is already well above bufio's own 64KB limit;"+
+					" raise it further if the population holds"+
+					" pathologically long entries that would otherwise"+
+					" fail with a scan error",
+				defaultMaxLineLen),
+		)
+
+		commonAlgoSubvals := []string{
+			"threshold", "minStrLen", "minStrLenAppliesTo",
+			"mapToLowerCase", "stripRunes",
+		}
+		ngramAlgoSubvals := append(
+			append([]string{}, commonAlgoSubvals...),
+			"nGramLen", "minNGramLen", "overflowNGrams", "ngramStep",
+			"shingleWords", "maxNGramCacheSize")
+
+		algoDetailsAVals := psetter.AllowedVals[string]{
+			strdist.AlgoNameScaledLevenshtein: "a scaled Levenshtein algorithm",
+			strdist.AlgoNameLevenshtein:       "a Levenshtein algorithm",
+			AlgoNameDamerauLevenshtein: "a Levenshtein algorithm that" +
+				" treats an adjacent transposition as a single edit",
+			strdist.AlgoNameCosine:          "a cosine algorithm",
+			strdist.AlgoNameHamming:         "a Hamming algorithm",
+			strdist.AlgoNameJaccard:         "a Jaccard algorithm",
+			strdist.AlgoNameWeightedJaccard: "a weighted Jaccard algorithm",
+		}
+		algoDetailsDflt := algoParams{
+			nGramLen:           dfltNGramLen,
+			nGramStep:          dfltNGramStep,
+			maxNGramCacheSize:  dfltMaxNGramCacheSize,
+			minStrLenAppliesTo: minStrLenApplyBoth,
+		}
+
+		algoDetailsAggressive := algoDetailsDflt
+		algoDetailsAggressive.threshold = 5.0
+		algoDetailsAggressive.useGivenThreshold = true
+		algoDetailsAggressive.mapToLowerCase = true
+
+		algoDetailsConservative := algoDetailsDflt
+		algoDetailsConservative.threshold = 1.0
+		algoDetailsConservative.useGivenThreshold = true
+
+		algoDetailsSetter := ListMultiSetter[string, algoParams]{
+			Value: &prog.algoParams,
+			MultiSetterBase: MultiSetterBase[string, algoParams]{
+				DfltEntryVal: algoDetailsDflt,
+				Presets: map[string]algoParams{
+					"aggressive":   algoDetailsAggressive,
+					"conservative": algoDetailsConservative,
+				},
+				AVals: algoDetailsAVals,
+				AllowedSubvalNames: map[string][]string{
+					strdist.AlgoNameLevenshtein:       commonAlgoSubvals,
+					strdist.AlgoNameScaledLevenshtein: commonAlgoSubvals,
+					strdist.AlgoNameHamming:           commonAlgoSubvals,
+					AlgoNameDamerauLevenshtein:        commonAlgoSubvals,
+					strdist.AlgoNameCosine:            ngramAlgoSubvals,
+					strdist.AlgoNameJaccard:           ngramAlgoSubvals,
+					strdist.AlgoNameWeightedJaccard:   ngramAlgoSubvals,
+				},
+				FinalCheck: func(name string, ap algoParams) error {
+					if ap.minNGramLen > 0 && ap.minNGramLen > ap.nGramLen {
+						return fmt.Errorf(
+							"minNGramLen (%d) must not exceed nGramLen (%d)",
+							ap.minNGramLen, ap.nGramLen)
+					}
+
+					if ap.useGivenThreshold {
+						if err := checkThreshold(name, ap.threshold); err != nil {
+							return err
+						}
+					}
+
+					return nil
+				},
+			},
+		}
+		algoDetailsSetter.EntryVal = algoDetailsDflt
+		prog.algoSetter = &algoDetailsSetter
+		algoDetailsSetter.EntryValSetterMap = map[string]EntryValSetter{
+			"nGramLen": {
+				Setter: psetter.Int[int]{
+					Value: &algoDetailsSetter.EntryVal.nGramLen,
+					Checks: []check.ValCk[int]{
+						check.ValGT(0),
+					},
+				},
+			},
+			"minNGramLen": {
+				Setter: psetter.Int[int]{
+					Value: &algoDetailsSetter.EntryVal.minNGramLen,
+					Checks: []check.ValCk[int]{
+						check.ValGT(0),
+					},
+				},
+			},
+			"overflowNGrams": {
+				Setter: psetter.Bool{
+					Value: &algoDetailsSetter.EntryVal.overflowTheSource,
+				},
+			},
+			"ngramStep": {
+				Setter: psetter.Int[int]{
+					Value: &algoDetailsSetter.EntryVal.nGramStep,
+					Checks: []check.ValCk[int]{
+						check.ValGT(0),
+					},
+				},
+			},
+			"maxNGramCacheSize": {
+				Setter: psetter.Int[int]{
+					Value: &algoDetailsSetter.EntryVal.maxNGramCacheSize,
+					Checks: []check.ValCk[int]{
+						check.ValGT(0),
+					},
+				},
+			},
+			"shingleWords": {
+				Setter: psetter.Bool{
+					Value: &algoDetailsSetter.EntryVal.shingleWords,
+				},
+			},
+			"threshold": {
+				Setter: psetter.Float[float64]{
+					Value: &algoDetailsSetter.EntryVal.threshold,
+				},
+				PostActionFuncs: []MultiSetterActionFunc{
+					func(_, _ string) error {
+						algoDetailsSetter.EntryVal.useGivenThreshold = true
+						return nil
+					},
+				},
+			},
+			"minStrLen": {
+				Setter: psetter.Int[int]{
+					Value: &algoDetailsSetter.EntryVal.minStrLen,
+				},
+			},
+			"minStrLenAppliesTo": {
+				Setter: psetter.Enum[string]{
+					Value: &algoDetailsSetter.EntryVal.minStrLenAppliesTo,
+					AllowedVals: psetter.AllowedVals[string]{
+						minStrLenApplyTarget: "exclude only targets" +
+							" shorter than minStrLen",
+						minStrLenApplyPopulation: "exclude only population" +
+							" words shorter than minStrLen",
+						minStrLenApplyBoth: "exclude both targets and" +
+							" population words shorter than minStrLen" +
+							" (the default)",
+					},
+				},
+			},
+			"mapToLowerCase": {
+				Setter: psetter.Bool{
+					Value: &algoDetailsSetter.EntryVal.mapToLowerCase,
+				},
+				PostActionFuncs: []MultiSetterActionFunc{
+					func(_, _ string) error {
+						algoDetailsSetter.EntryVal.useGivenMapToLowerCase = true
+						return nil
+					},
+				},
+			},
+			"stripRunes": {
+				Setter: psetter.String[string]{
+					Value: &algoDetailsSetter.EntryVal.stripRunes,
+					Checks: []check.ValCk[string]{
+						func(s string) error {
+							runeIdx := map[rune]int{}
+							runeSlc := []rune(s)
+							for i, r := range runeSlc {
+								if idx, ok := runeIdx[r]; ok {
+									return fmt.Errorf(
+										"%q contains duplicate runes:"+
+											" %q appears at both"+
+											" the %d%s and %d%s positions",
+										s, r,
+										i+1, english.OrdinalSuffix(i+1),
+										idx+1, english.OrdinalSuffix(idx+1))
+								}
+
+								runeIdx[r] = i
+							}
+							return nil
+						},
+					},
+				},
+				PostActionFuncs: []MultiSetterActionFunc{
+					func(_, _ string) error {
+						algoDetailsSetter.EntryVal.useGivenStripRunes = true
+						return nil
+					},
+				},
+			},
+		}
+		algoDetailsSetter.EntryValSMAliases = map[string]string{
+			"nGramLength":      "nGramLen",
+			"ngLength":         "nGramLen",
+			"ngLen":            "nGramLen",
+			"minNGramLength":   "minNGramLen",
+			"ngMinLen":         "minNGramLen",
+			"ngMinLength":      "minNGramLen",
+			"overflow":         "overflowNGrams",
+			"Overflow":         "overflowNGrams",
+			"ngStep":           "ngramStep",
+			"step":             "ngramStep",
+			"ngCacheSize":      "maxNGramCacheSize",
+			"ngramCacheSize":   "maxNGramCacheSize",
+			"cacheSize":        "maxNGramCacheSize",
+			"shingles":         "shingleWords",
+			"wordShingles":     "shingleWords",
+			"minStrLenApplies": "minStrLenAppliesTo",
+			"minLenAppliesTo":  "minStrLenAppliesTo",
+			"Threshold":        "threshold",
+			"toLower":          "mapToLowerCase",
+			"mapToLowercase":   "mapToLowerCase",
+			"stripChars":       "stripRunes",
+		}
+
+		ps.Add(paramNameAlgo,
+			&algoDetailsSetter,
+			"the algorithm and associated details. Not needed if "+
+				paramNameRequestFile+" is given",
+		)
+
+		ps.Add(paramNameAlgoFile,
+			psetter.Pathname{
+				Value:       &prog.algoFile,
+				Expectation: filecheck.FileNonEmpty(),
+			},
+			"the name of a file of algorithm specs, one per line, in the"+
+				" same name=subval=\"...\" form as "+paramNameAlgo+
+				", to be added to those given directly on the command"+
+				" line. Blank lines and lines starting with '#' are"+
+				" skipped",
+		)
+
+		ps.Add(paramNameQueryFile,
+			psetter.Pathname{
+				Value:       &prog.queryFile,
+				Expectation: filecheck.FileNonEmpty(),
+			},
+			"the name of a file of strings to match, one per line, blank"+
+				" lines being skipped. Its entries are combined with any"+
+				" strings given as command-line arguments, avoiding the"+
+				" ARG_MAX limit when there are thousands of them",
+		)
+
+		ps.Add(paramNameUniqueQueries,
+			psetter.Bool{Value: &prog.uniqueQueries},
+			"remove duplicate entries from the search words, keeping"+
+				" the first occurrence of each, before the finders run."+
+				" Left unset, a search word that appears twice produces"+
+				" two identical blocks of rows in the report",
+		)
+
+		ps.Add(paramNameMaxResults,
+			psetter.Int[int]{
+				Value:  &prog.maxResults,
+				Checks: []check.ValCk[int]{check.ValGT(0)},
+			},
+			"the maximum number of results to show.",
+		)
+
+		ps.Add(paramNameTransliterate,
+			psetter.Bool{Value: &prog.transliterate},
+			"transliterate non-Latin scripts to a Latin approximation"+
+				" before comparing words, so that, for instance, the"+
+				" Cyrillic \"Москва\" can match \"Moskva\". The original"+
+				" population word is still shown in the results.",
+		)
+
+		ps.Add(paramNameStatusFile,
+			psetter.Pathname{Value: &prog.statusFile},
+			"the name of a file to write a JSON summary of the run to:"+
+				" the number of targets, how many had matches, and the"+
+				" per-algorithm match counts. It is written even if the"+
+				" run exits early, so a controlling process always has"+
+				" a status to inspect",
+		)
+
+		ps.Add(paramNameCheckSymmetry,
+			psetter.Bool{Value: &prog.checkSymmetry},
+			"for each configured algorithm, check whether it gives the"+
+				" same distance in both directions for every combination"+
+				" of a target and a population word, and report whether"+
+				" it is symmetric. Some algorithms, such as weighted"+
+				" Jaccard, are not. No search is performed",
+		)
+
+		ps.Add(paramNameSymmetrise,
+			psetter.Bool{Value: &prog.symmetrise},
+			"average the distance in both directions for every algorithm,"+
+				" so that dist(a, b) always equals dist(b, a) even for"+
+				" algorithms that are not naturally symmetric",
+		)
+
+		ps.Add(paramNamePartial,
+			psetter.Bool{Value: &prog.partial},
+			"match the target against the best-matching equal-length"+
+				" window of each longer population word, rather than"+
+				" comparing the whole strings. This finds the best"+
+				" matching substring instead of penalising population"+
+				" words for being longer than the target, at the cost"+
+				" of one extra distance calculation per rune of length"+
+				" difference",
+		)
+
+		ps.Add(paramNameEmptyCell,
+			psetter.String[string]{Value: &prog.emptyCell},
+			"the string to print for a result distance or value cell"+
+				" when there is no result to show, in place of the"+
+				" default blank cell",
+		)
+
+		ps.Add(paramNameCompact,
+			psetter.Bool{Value: &prog.compact},
+			"show a reduced report with just the target, the algorithm"+
+				" name and the closest match's distance and value,"+
+				" dropping the threshold, minimum string length, case"+
+				" and strip-runes columns along with any results beyond"+
+				" the closest",
+		)
+
+		ps.Add(paramNameFreqColumn,
+			psetter.Int[int]{
+				Value:  &prog.frequencyColumn,
+				Checks: []check.ValCk[int]{check.ValGT(0)},
+			},
+			"treat "+paramNameWordFile+" as CSV and take the population"+
+				" word's frequency from this column (the word itself is"+
+				" always taken from the first column). 1-based",
+		)
+
+		ps.Add(paramNamePreferFreq,
+			psetter.Bool{Value: &prog.preferFrequent},
+			"when two results for a target are equally distant, prefer"+
+				" the more frequent population word. Requires "+
+				paramNameFreqColumn+" to have been given; it only ever"+
+				" reorders equal-distance results, it never lets a more"+
+				" frequent but worse-matching word win",
+		)
+
+		ps.Add(paramNameSortAlgos,
+			psetter.Enum[sortAlgosBy]{
+				Value: &prog.sortAlgosBy,
+				AllowedVals: psetter.AllowedVals[sortAlgosBy]{
+					sortAlgosInput:     "the order the algo parameters were given in",
+					sortAlgosName:      "alphabetically by algorithm name",
+					sortAlgosThreshold: "by the algorithm's threshold, ascending",
+				},
+			},
+			"how to order the algorithm blocks within each target's"+
+				" report, rather than always using the order the "+
+				paramNameAlgo+" parameters were given in. Useful for"+
+				" comparing reports across runs that pass "+
+				paramNameAlgo+" in different orders",
+		)
+
+		ps.Add(paramNameSkipEmpty,
+			psetter.Bool{Value: &prog.skipEmptyTargets},
+			"omit a target's whole block from the report if no finder"+
+				" found any match for it, rather than printing a block"+
+				" of empty result rows. The number skipped is given in"+
+				" a footer line. Distinct from "+paramNameEmptyCell+
+				", which only changes how an individual empty result"+
+				" cell is rendered",
+		)
+
+		ps.Add(paramNameRequireMatch,
+			psetter.Bool{Value: &prog.requireMatches},
+			"exit with a non-zero status if any target had no match"+
+				" from any finder. Combines sensibly with "+
+				paramNameSkipEmpty+" to both declutter the report and"+
+				" fail a calling script when coverage is incomplete",
+			param.AltNames(paramNameFailOnNoMatch),
+		)
+
+		ps.Add(paramNameGoldFile,
+			psetter.Pathname{
+				Value:       &prog.goldFile,
+				Expectation: filecheck.FileNonEmpty(),
+			},
+			"the name of a CSV file of target,expectedMatch records"+
+				" giving the correct population word for each target."+
+				" Required by "+paramNameROC,
+		)
+
+		ps.Add(paramNameROC,
+			psetter.Bool{Value: &prog.roc},
+			"for each configured algorithm, sweep every distance value"+
+				" observed between a "+paramNameGoldFile+" target and a"+
+				" population word as a candidate threshold, and write"+
+				" the resulting true- and false-positive rates as CSV"+
+				" (algorithm,threshold,tpr,fpr) to stdout, so an ROC"+
+				" curve can be plotted and an operating threshold"+
+				" chosen. Requires "+paramNameGoldFile+"; the targets"+
+				" given on the command line are not used",
+		)
+
+		ps.Add(paramNameUseTrie,
+			psetter.Bool{Value: &prog.useTrie},
+			"build a trie over the population and use it to narrow the"+
+				" candidates scored for each target down to those within"+
+				" a computed edit-distance budget, rather than scoring"+
+				" every population word. This is only a safe optimisation"+
+				" for algorithms whose distance is edit-distance bounded"+
+				" (Levenshtein, scaled Levenshtein and Hamming); for any"+
+				" other algorithm the results are unaffected but the"+
+				" whole population is still scored. Intended for very"+
+				" large, prefix-heavy dictionaries",
+		)
+
+		ps.Add(paramNameTopK,
+			psetter.Bool{Value: &prog.topK},
+			"scan the population with a bounded max-heap of size "+
+				paramNameMaxResults+" instead of collecting every match"+
+				" and sorting the lot, so that a huge population with a"+
+				" small "+paramNameMaxResults+" doesn't pay for a full"+
+				" sort it doesn't need. The retained matches and their"+
+				" order are unaffected, but the reported number of"+
+				" matches is capped at "+paramNameMaxResults+" too, since"+
+				" the true total is no longer counted. Takes precedence"+
+				" over "+paramNameUseTrie,
+		)
+
+		ps.Add(paramNameExplainAlgo,
+			psetter.Bool{Value: &prog.explainAlgoSyntax},
+			"print the exact syntax accepted by "+paramNameAlgo+
+				" - the name-part and sub-value grammar, including any"+
+				" alias handling - and exit without doing anything else."+
+				" Use this to see precisely what will be matched when"+
+				" building a complex "+paramNameAlgo+" value",
+		)
+
+		ps.Add(paramNameRequestFile,
+			psetter.Pathname{
+				Value:       &prog.requestFile,
+				Expectation: filecheck.FileNonEmpty(),
+			},
+			"the name of a JSON file describing the population (or"+
+				" word-file), the algorithms, the targets and the"+
+				" maximum number of results, for a single self-describing"+
+				" invocation. It overrides "+paramNameWordFile+" and "+
+				paramNameAlgo+" if given, and the targets take the place"+
+				" of any given on the command line. The results are"+
+				" written to stdout as JSON rather than as a report",
+		)
+
+		ps.Add(paramNameTokenise,
+			psetter.Bool{Value: &prog.tokenise},
+			"split targets and population words into tokens and rejoin"+
+				" them with a single space before comparing, so that"+
+				" phrases differing only in the delimiter between their"+
+				" words - a hyphen or a slash rather than whitespace,"+
+				" say - compare as equal. Splits on whitespace unless "+
+				paramNameTokenRegex+" is given. The original population"+
+				" word is still shown in the results",
+		)
+
+		ps.Add(paramNameTokenRegex,
+			psetter.Regexp{Value: &prog.tokenRegex},
+			"the regular expression used to split a phrase into tokens"+
+				" for "+paramNameTokenise+", in place of the default of"+
+				" any run of whitespace. Useful for structured"+
+				" identifiers and addresses, for instance splitting on"+
+				" any of a hyphen, a slash or whitespace",
+		)
+
+		ps.Add(paramNameFlatSorted,
+			psetter.Bool{Value: &prog.flatSorted},
+			"instead of the tabular report, write every"+
+				" (target, algorithm, distance, match) tuple, up to "+
+				paramNameMaxResults+" per target/algorithm pair, as one"+
+				" tab-separated line to stdout, sorted by"+
+				" (target, algorithm, distance, match) with distances"+
+				" formatted to a fixed precision. This makes two runs"+
+				" against the same inputs trivially diff-able, for"+
+				" spotting regressions across tool versions",
+		)
+
+		ps.Add(paramNameTranspose,
+			psetter.Bool{Value: &prog.transpose},
+			fmt.Sprintf(
+				"transpose the tabular report so each finder is a row"+
+					" and each target is a column, showing the best"+
+					" match as \"distance: value\" in each cell. Only"+
+					" sensible for a handful of targets; refuses to run"+
+					" with more than %d",
+				maxTransposeTargets),
+		)
+
+		ps.Add(paramNameEnsemble,
+			psetter.Bool{Value: &prog.ensemble},
+			"instead of one column block per algorithm, fuse the"+
+				" finders into a single ranking: each finder's"+
+				" distances are normalized to [0,1], a candidate not"+
+				" returned by a finder scores 1 (worst-case) for it,"+
+				" and the fused score for a candidate is the mean"+
+				" across finders. Writes the fused-best "+
+				paramNameMaxResults+" candidates per target as"+
+				" tab-separated lines to stdout",
+		)
+
+		ps.Add(paramNameDedupAlgos,
+			psetter.Bool{Value: &prog.dedupAlgos},
+			"if two or more "+paramNameAlgo+" parameters resolve to an"+
+				" identical configuration - the same algorithm name and"+
+				" the same sub-values, whether because they were given"+
+				" twice or because an alias resolved to the same"+
+				" config - build the strdist.Finder once and reuse it,"+
+				" including its n-gram cache, instead of building an"+
+				" identical Finder for each. Leave this unset if"+
+				" duplicate report columns are wanted",
+		)
+
+		ps.Add(paramNameRawBest,
+			psetter.Bool{Value: &prog.rawBest},
+			"print only the single best match for the (single) target,"+
+				" using the first configured algorithm (or, with "+
+				paramNameSortAlgos+", the first after sorting): no"+
+				" decoration, newline-terminated, and nothing else on"+
+				" stdout. Errors and diagnostics go to stderr. If there"+
+				" is no match, nothing is printed and the exit status"+
+				" is non-zero. Intended for shell substitution, e.g."+
+				" best=$(strdistmatch ... word)",
+		)
+
+		ps.Add(paramNameDetectEquiv,
+			psetter.Bool{Value: &prog.detectEquivalent},
+			"instead of the search report, compute each configured"+
+				" algorithm's top "+paramNameMaxResults+" matches for"+
+				" every target and report any pair of algorithms whose"+
+				" results - values, distances and order - are identical"+
+				" for every target, suggesting that one of the pair is"+
+				" redundant for this population and this set of"+
+				" targets. Helps prune a comparison set of "+
+				paramNameAlgo+" configurations",
+		)
+
+		ps.Add(paramNameMinDistance,
+			psetter.Float[float64]{
+				Value:  &prog.minDistance,
+				Checks: []check.ValCk[float64]{check.ValGE(0.0)},
+			},
+			"a floor below which results are discarded, applied after"+
+				" each finder's results are found and before the"+
+				" top-N cut. Useful in deduplication workflows to"+
+				" exclude exact matches (distance 0) and other"+
+				" trivially-close strings, keeping only genuinely"+
+				" different near-matches. Distinct from a finder's"+
+				" threshold, which is a ceiling",
+		)
+
+		ps.Add(paramNameMaxDistance,
+			psetter.Float[float64]{
+				Value:  &prog.maxDistance,
+				Checks: []check.ValCk[float64]{check.ValGE(0.0)},
+			},
+			"a ceiling above which results are discarded, applied"+
+				" after each finder's results are found and before"+
+				" the top-N cut. "+paramNameMaxResults+" only caps"+
+				" the count; this is a quality cutoff instead."+
+				" Distinct from a finder's own threshold, which"+
+				" governs membership rather than what gets"+
+				" displayed",
+		)
+
+		ps.Add(paramNameWorst,
+			psetter.Bool{Value: &prog.worst},
+			"show the furthest matches that still passed the"+
+				" finder instead of the closest, useful for seeing"+
+				" exactly where a threshold cuts off. The distance"+
+				" column still shows the real distances",
+		)
+
+		ps.Add(paramNameGroupByDist,
+			psetter.Bool{Value: &prog.groupByDistance},
+			"instead of listing individual matches, for each target and"+
+				" algorithm group its matches by their exact distance"+
+				" value and write the count at each distance as"+
+				" tab-separated (target, algorithm, distance, count)"+
+				" lines to stdout, sorted by (target, algorithm,"+
+				" distance). A compact summary of how matches cluster"+
+				" by distance, useful when choosing a cutoff threshold",
+		)
+
+		ps.Add(paramNameGroupBy,
+			psetter.Enum[groupBy]{
+				Value: &prog.groupBy,
+				AllowedVals: psetter.AllowedVals[groupBy]{
+					groupByTarget: "today's layout: each target followed by" +
+						" the finders it was scored against",
+					groupByAlgorithm: "each finder followed by every" +
+						" target's row against it, for reading all of one" +
+						" algorithm's results as a single block",
+				},
+			},
+			"how to organise the rows of the main tabular report."+
+				" Only affects that report, not "+paramNameOutputFormat+
+				" json/csv or any of the other output modes",
+		)
+
+		ps.Add(paramNameFullGrid,
+			psetter.Bool{Value: &prog.fullGrid},
+			"always print the full grid report, even if stdout is a"+
+				" terminal narrower than the grid. Normally, when"+
+				" stdout is a terminal too narrow for the full grid,"+
+				" the tool automatically falls back to the "+
+				paramNameCompact+" layout; this has no effect if "+
+				paramNameCompact+" is also given, or if stdout is"+
+				" not a terminal",
+		)
+
+		ps.Add(paramNameMeasureMemory,
+			psetter.Bool{Value: &prog.measureMemory},
+			"instead of searching and reporting matches, run each"+
+				" algorithm's search across all the targets in turn"+
+				" and report the memory it allocated while doing so,"+
+				" as tab-separated (algorithm, allocated bytes,"+
+				" allocations) lines to stdout. A GC is forced before"+
+				" each algorithm is measured to reduce cross-algorithm"+
+				" attribution error but Go's garbage collector still"+
+				" makes precise attribution impossible, so treat the"+
+				" figures as approximate: useful for comparing the"+
+				" memory-hungry n-gram algorithms against lightweight"+
+				" ones, not as an exact accounting",
+		)
+
+		ps.Add(paramNameBenchmark,
+			psetter.Bool{Value: &prog.benchmark},
+			"instead of searching and reporting matches, run each"+
+				" algorithm's search across all the targets, repeated "+
+				paramNameBenchmarkReps+" times, discard the matches,"+
+				" and report elapsed time and throughput as"+
+				" tab-separated lines to stdout. The population and"+
+				" finders are built exactly as for a normal search",
+		)
+
+		ps.Add(paramNameBenchmarkReps,
+			psetter.Int[int]{
+				Value:  &prog.benchmarkReps,
+				Checks: []check.ValCk[int]{check.ValGT(0)},
+			},
+			"the number of times to repeat the search across all"+
+				" targets, for "+paramNameBenchmark,
+		)
+
+		ps.Add(paramNameProgress,
+			psetter.Bool{Value: &prog.progress},
+			"periodically write, to stderr, how many targets have"+
+				" been processed out of the total, throttled to"+
+				" roughly once a second. Written to stderr rather"+
+				" than stdout so it doesn't corrupt a redirected"+
+				" report, useful when searching a large population"+
+				" gives no feedback for minutes",
+		)
+
+		ps.Add(paramNameHideCommon,
+			psetter.Bool{Value: &prog.hideCommonColumns},
+			"in the full (non-"+paramNameCompact+") report, omit any"+
+				" configuration column (details, threshold, minimum"+
+				" str len, map to lower, strip runes) whose value is"+
+				" identical across every configured "+paramNameAlgo+
+				", and print a footer listing the hidden columns and"+
+				" their shared value. Has no effect with "+
+				paramNameCompact+", which never shows these columns",
+		)
+
+		ps.Add(paramNameColumns,
+			psetter.EnumList[reportColumn]{
+				Value: &prog.columns,
+				AllowedVals: psetter.AllowedVals[reportColumn]{
+					reportColumnName:      "the algorithm name",
+					reportColumnDetails:   "the algorithm details",
+					reportColumnThreshold: "the finder's threshold",
+					reportColumnMinStrLen: "the finder's minimum string length",
+					reportColumnMinStrLenAppliesTo: "what the minimum" +
+						" string length applies to",
+					reportColumnMapToLower: "whether the finder maps to lower case",
+					reportColumnStripRunes: "the runes the finder strips",
+					reportColumnCount:      "the number of results found",
+				},
+			},
+			"a comma-separated list of the Finder-configuration columns"+
+				" to show in the full (non-"+paramNameCompact+") report,"+
+				" in place of showing them all. The target column and"+
+				" each result's distance and value are always shown."+
+				" Useful when "+paramNameAlgo+" is repeated many times"+
+				" and only the algorithm name and results are of interest",
+		)
+
+		ps.Add(paramNameTargetCharset,
+			psetter.Regexp{Value: &prog.targetCharset},
+			"a regular expression that every target must match. This"+
+				" is a data-quality check, to catch stray control"+
+				" characters, mixed scripts and other encoding"+
+				" problems in the targets before they produce"+
+				" misleading distances. A target that doesn't match"+
+				" is skipped, with a warning, unless "+paramNameStrict+
+				" is also given, in which case it aborts the program",
+		)
+
+		ps.Add(paramNameStrict,
+			psetter.Bool{Value: &prog.strict},
+			"abort, naming the offending value, on the first target"+
+				" that fails the "+paramNameTargetCharset+" check,"+
+				" rather than skipping it with a warning. Has no"+
+				" effect unless "+paramNameTargetCharset+" is given",
+		)
+
+		ps.Add(paramNameAuditFile,
+			psetter.Pathname{Value: &prog.auditFile},
+			"the name of a file to write a structured audit trail to,"+
+				" as JSON Lines: one object per accepted match, giving"+
+				" the target, the matched value, the algorithm, the"+
+				" distance, the threshold and the preprocessing"+
+				" (lowercasing, stripped runes) in effect. Separate"+
+				" from the display output, for compliance use cases"+
+				" where fuzzy-matching decisions must be auditable. It"+
+				" is written even if the run exits early",
+		)
+
+		ps.Add(paramNameMaxConcurrent,
+			psetter.Int[int]{
+				Value:  &prog.maxConcurrentAlgos,
+				Checks: []check.ValCk[int]{check.ValGE(1)},
+			},
+			"the maximum number of algorithms to search with"+
+				" concurrently for a given target. Bounds the memory"+
+				" used by running several n-gram algorithms, with"+
+				" their caches, at once; independent of any"+
+				" target-level concurrency. Defaults to GOMAXPROCS;"+
+				" set to 1 to search serially",
+			param.AltNames(paramNameJobs, "j"),
+		)
+
+		ps.Add(paramNameMaxConcurrentTargets,
+			psetter.Int[int]{
+				Value:  &prog.maxConcurrentTargets,
+				Checks: []check.ValCk[int]{check.ValGE(1)},
+			},
+			"the maximum number of targets to search for concurrently."+
+				" Each still searches with up to "+paramNameMaxConcurrent+
+				" algorithms at once, so the two multiply together to"+
+				" bound total concurrency. The population is read-only"+
+				" during the search, so it's safe to share across"+
+				" targets. Results are still printed in the order the"+
+				" targets were given. Defaults to 1, searching one"+
+				" target at a time",
+		)
+
+		ps.Add(paramNameGraphemeClust,
+			psetter.Bool{Value: &prog.graphemeClusters},
+			"make the rune-based algorithms (Hamming, Levenshtein) treat"+
+				" a base rune together with any combining marks that"+
+				" attach to it - an accented letter or an emoji"+
+				" modifier sequence - as a single unit, rather than"+
+				" counting each combining mark as a separate edit."+
+				" Defaults to off, comparing rune-by-rune, to preserve"+
+				" existing behaviour",
+		)
+
+		ps.Add(paramNameWithConfidence,
+			psetter.Bool{Value: &prog.withConfidence},
+			"show a confidence score alongside the "+paramNameRawBest+
+				" suggestion: the normalised gap between the best and"+
+				" second-best distances, in the range 0 (a near-tie,"+
+				" not to be trusted) to 1 (nothing else came close)."+
+				" Has no effect without "+paramNameRawBest,
+		)
+
+		ps.Add(paramNameServe,
+			psetter.String[string]{Value: &prog.serveAddr},
+			"run as a daemon, listening on this address (host:port) and"+
+				" answering queries over a line-oriented TCP protocol:"+
+				" each line sent is a target word, answered with its"+
+				" matches from every finder, one per line as"+
+				" \"algorithm<tab>distance<tab>value\", followed by a"+
+				" blank line. Sending the process a SIGHUP re-reads "+
+				paramNameWordFile+" and swaps in the new population,"+
+				" so an evolving dictionary can be picked up without"+
+				" restarting the daemon. No target strings should be"+
+				" given on the command line with this",
+		)
+
+		ps.Add(paramNameWhyTarget,
+			psetter.String[string]{Value: &prog.whyTarget},
+			"diagnose why "+paramNameWhyWord+" does, or doesn't, match"+
+				" this target, under each configured finder: report the"+
+				" preprocessed form of each string, any "+
+				paramNameMinStrLen+" exclusion, the computed distance and"+
+				" the finder's threshold. Must be given with "+
+				paramNameWhyWord+
+				"; no target strings should be given on the command line"+
+				" with this",
+		)
+
+		ps.Add(paramNameWhyWord,
+			psetter.String[string]{Value: &prog.whyWord},
+			"the population word to diagnose against "+
+				paramNameWhyTarget+", see there for details",
+		)
+
+		ps.Add(paramNameParquetFile,
+			psetter.Pathname{Value: &prog.parquetFile},
+			"the name of a file to write the results to in Parquet"+
+				" format, as rows of (target, algorithm, distance,"+
+				" value, rank), instead of printing the tabular report."+
+				" Suited to large populations of targets destined for"+
+				" further analysis in tools such as pandas or Spark",
+		)
+
+		ps.Add(paramNamePerAlgoBudget,
+			psetter.Duration{
+				Value:  &prog.perAlgoBudget,
+				Checks: []check.Duration{check.ValGT(time.Duration(0))},
+			},
+			"stop scanning the population for each finder once this"+
+				" much time has been spent on it, returning only the"+
+				" matches found so far. This bypasses "+paramNameUseTrie+
+				" and any "+paramNameMinStrLen+" applies-to setting, and"+
+				" the report gains a results/truncated column showing"+
+				" which searches were cut short - their matches are"+
+				" only a partial, approximate result. Intended for"+
+				" latency-bounded services with a large population",
+		)
+
+		ps.Add(paramNamePivotCSVFile,
+			psetter.Pathname{Value: &prog.pivotCSVFile},
+			"the name of a CSV file to write the results to, instead of"+
+				" printing the tabular report, as a pair of matrices with"+
+				" targets as rows and algorithms as columns: one of the"+
+				" top match's distance, one of the matched value. A cell"+
+				" for a target with no match under a given algorithm is"+
+				" left blank. More amenable to cross-algorithm comparison"+
+				" in a spreadsheet than "+paramNameFlatSorted,
+		)
+
+		ps.Add(paramNameInvert,
+			psetter.Bool{Value: &prog.invert},
+			"invert the report: instead of one row per target showing its"+
+				" best-matching population words, print one CSV row per"+
+				" population word that matched at least one target, giving"+
+				" how many targets it matched and, per "+paramNameInvertAgg+
+				", a single score combining the distances to them. This"+
+				" surfaces population entries that are broadly confusable"+
+				" across many targets rather than closely tied to just"+
+				" one. Takes precedence over "+paramNameOutputFormat,
+		)
+
+		ps.Add(paramNameInvertAgg,
+			psetter.Enum[invertAggregate]{
+				Value: &prog.invertAggregate,
+				AllowedVals: psetter.AllowedVals[invertAggregate]{
+					invertAggregateMin: "the closest of the word's matching" +
+						" targets",
+					invertAggregateMean: "the mean distance across the" +
+						" word's matching targets",
+					invertAggregateMax: "the furthest of the word's" +
+						" matching targets",
+				},
+			},
+			"how to combine the distances for a population word that"+
+				" matches several targets into the single score reported"+
+				" for that word in the "+paramNameInvert+" report."+
+				" Has no effect without "+paramNameInvert,
+		)
+
+		ps.Add(paramNameOutputFormat,
+			psetter.Enum[outputFormat]{
+				Value: &prog.outputFormat,
+				AllowedVals: psetter.AllowedVals[outputFormat]{
+					outputFormatTable: "the column-formatted report",
+					outputFormatJSON: "a JSON array, one entry per target," +
+						" each giving the finders tried and their matches," +
+						" for scripts that would otherwise have to parse" +
+						" the table",
+					outputFormatCSV: "one row per target/algorithm" +
+						" combination, suitable for loading into a" +
+						" spreadsheet",
+					outputFormatMarkdown: "one row per target/algorithm" +
+						" combination, written as a GitHub-flavored" +
+						" Markdown table, suitable for pasting into an" +
+						" issue or pull request",
+				},
+			},
+			"how to report the results. The json, csv and markdown"+
+				" formats bypass the tabular report entirely and still"+
+				" respect "+paramNameMaxResults,
+		)
+
+		ps.Add(paramNameNoHeader,
+			psetter.Bool{Value: &prog.noHeader},
+			"suppress the header row of the main tabular report, so"+
+				" that the rows from several runs can be concatenated,"+
+				" or fed straight into another parser, without a"+
+				" header row appearing part-way through the stream."+
+				" Only affects the table format; the json and csv"+
+				" formats already handle headers differently and are"+
+				" unaffected",
+		)
+
+		ps.Add(paramNameShowSimilarity,
+			psetter.Bool{Value: &prog.showSimilarity},
+			"add a similarity column alongside each result's distance,"+
+				" showing that distance normalized to a [0,1] scale,"+
+				" most similar at 1. The normalization is chosen by"+
+				" algorithm: Levenshtein, Damerau-Levenshtein and"+
+				" Hamming distances are"+
+				" divided by the length of the longer string compared;"+
+				" the rest (cosine, Jaccard, weighted Jaccard, scaled"+
+				" Levenshtein) already return a distance in that range"+
+				" and are left as they are. This makes distances from"+
+				" different algorithms comparable side by side",
+		)
+
+		ps.Add(paramNameInteractive,
+			psetter.Bool{Value: &prog.interactive},
+			"after building the finders and population once, read query"+
+				" strings one per line from standard input and print the"+
+				" result table for each until EOF, so that a"+
+				" multi-million-word population doesn't have to be"+
+				" reloaded to test one query at a time. A blank line is"+
+				" ignored; end-of-input (Ctrl-D) exits cleanly",
+		)
+
+		ps.Add(paramNameWarnDefaults,
+			psetter.Bool{Value: &prog.warnDefaults},
+			"print a note to standard error for every finder whose"+
+				" threshold subval wasn't given explicitly, naming the"+
+				" default threshold that was used instead. Comparing"+
+				" algorithms against each other's implicit, and"+
+				" possibly mismatched, cutoffs is a common source of"+
+				" misread results",
+		)
+
+		ps.Add(paramNameDefaultToLower,
+			psetter.Bool{Value: &prog.defaultMapToLowerCase},
+			"apply the mapToLowerCase behaviour to every "+
+				paramNameAlgo+" entry that doesn't set its own"+
+				" mapToLowerCase subval, instead of repeating"+
+				" mapToLowerCase=\"true\" on each one",
+		)
+
+		ps.Add(paramNameDefaultStripRunes,
+			psetter.String[string]{Value: &prog.defaultStripRunes},
+			"apply this stripRunes value to every "+paramNameAlgo+
+				" entry that doesn't set its own stripRunes"+
+				" subval, instead of repeating it on each one",
+		)
+
+		ps.Add(paramNameShowTiming,
+			psetter.Bool{Value: &prog.showTiming},
+			"measure the wall-clock time each finder's search takes"+
+				" per target and print a summary of total and mean"+
+				" time per algorithm after the main results table."+
+				" The timing itself is not added as a results"+
+				" column",
+		)
+
+		ps.Add(paramNameSummary,
+			psetter.Bool{Value: &prog.summary},
+			"print a one-line total of the targets searched and the"+
+				" population size, followed by a small table, keyed"+
+				" by algorithm, of the number of results returned and"+
+				" the number of targets that got at least one match."+
+				" Printed after the main results table and "+
+				paramNameShowTiming+
+				"'s report, if any. The counts are the ones"+
+				" accumulated during the main loop, so this adds no"+
+				" extra search pass",
+		)
+
+		ps.Add(paramNameOutputFile,
+			psetter.Pathname{Value: &prog.outputFile},
+			"write the tabular report to this file instead of the"+
+				" standard output, truncating it if it already"+
+				" exists. This lets a comparison run be captured"+
+				" without shell redirection interfering with verbose"+
+				" logging. Only affects the default tabular report,"+
+				" not "+paramNameOutputFormat,
+		)
+
+		_ = ps.SetNamedRemHandler(param.NullRemHandler{}, "strings to match")
+
+		ps.AddFinalCheck(func() error {
+			if prog.explainAlgoSyntax {
+				return nil
+			}
+
+			if prog.roc && prog.goldFile == "" {
+				return fmt.Errorf("%s must be given if %s is set",
+					paramNameGoldFile, paramNameROC)
+			}
+
+			if (prog.whyTarget == "") != (prog.whyWord == "") {
+				return fmt.Errorf("%s and %s must be given together",
+					paramNameWhyTarget, paramNameWhyWord)
+			}
+
+			if prog.whyTarget != "" {
+				return nil
+			}
+
+			if prog.requestFile != "" {
+				return nil
+			}
+
+			if len(prog.wordFiles) == 0 {
+				return fmt.Errorf("%s must be set unless %s is given",
+					paramNameWordFile, paramNameRequestFile)
+			}
+
+			if len(prog.algoParams) == 0 && prog.algoFile == "" {
+				return fmt.Errorf("%s must be set unless %s or %s is given",
+					paramNameAlgo, paramNameAlgoFile, paramNameRequestFile)
+			}
+
+			return nil
+		})
+
+		return nil
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import "github.com/nickwells/strdist.mod/v2/strdist"
+
+// Default values used to populate an algoParams entry, shared between the
+// --algo parameter and --request-file parsing.
+const (
+	dfltNGramLen          = 3
+	dfltMaxNGramCacheSize = 3
+)
+
+// The values allowed for algoParams.minStrLenAppliesTo, controlling which
+// side of a comparison minStrLen excludes: the target, the population
+// word, or, as strdist.Finder.FindLike always does, both.
+const (
+	minStrLenApplyTarget     = "target"
+	minStrLenApplyPopulation = "population"
+	minStrLenApplyBoth       = "both"
+)
+
+// algoParams holds parameters needed to create an algo Finder
+type algoParams struct {
+	// the following values are used to construct the NGramConfig
+	nGramLen          int
+	minNGramLen       int
+	overflowTheSource bool
+	nGramStep         int
+	shingleWords      bool
+
+	maxNGramCacheSize int
+
+	// the following values are used to construct the FinderConfig
+	threshold              float64
+	useGivenThreshold      bool
+	minStrLen              int
+	minStrLenAppliesTo     string
+	mapToLowerCase         bool
+	useGivenMapToLowerCase bool
+	stripRunes             string
+	useGivenStripRunes     bool
+}
+
+// algoMaker is the type of a function taking an algoParams and returning a
+// strdist.Algo
+type algoMaker func(algoParams) (strdist.Algo, error)
+
+// ngramGroupKey identifies a group of finders whose underlying n-gram Algo
+// can be shared: same algorithm and same NGramConfig. Finders that differ
+// only in FinderConfig details such as threshold or minStrLen fall in the
+// same group and so can reuse one Algo (and its n-gram cache) between them.
+type ngramGroupKey struct {
+	algoName          string
+	nGramLen          int
+	minNGramLen       int
+	overflowTheSource bool
+}
+
+// ngramGroupKeyFor returns the ngramGroupKey for algoName/ap and true if
+// the Algo it builds has a shareable n-gram cache. Only the plain n-gram
+// algorithms qualify; the stepped/shingled variant builds a steppedNGramAlgo
+// instead, which doesn't use strdist's cache at all.
+func ngramGroupKeyFor(algoName string, ap algoParams) (ngramGroupKey, bool) {
+	switch algoName {
+	case strdist.AlgoNameCosine, strdist.AlgoNameJaccard, strdist.AlgoNameWeightedJaccard:
+	default:
+		return ngramGroupKey{}, false
+	}
+
+	if ap.nGramStep > dfltNGramStep || ap.shingleWords {
+		return ngramGroupKey{}, false
+	}
+
+	return ngramGroupKey{
+		algoName:          algoName,
+		nGramLen:          ap.nGramLen,
+		minNGramLen:       ap.minNGramLen,
+		overflowTheSource: ap.overflowTheSource,
+	}, true
+}
+
+var algoMakers = map[string]algoMaker{
+	strdist.AlgoNameLevenshtein: func(algoParams) (strdist.Algo, error) {
+		return strdist.LevenshteinAlgo{}, nil
+	},
+	AlgoNameDamerauLevenshtein: func(algoParams) (strdist.Algo, error) {
+		return damerauLevenshteinAlgo{}, nil
+	},
+	strdist.AlgoNameScaledLevenshtein: func(algoParams) (
+		strdist.Algo, error,
+	) {
+		return strdist.ScaledLevAlgo{}, nil
+	},
+	strdist.AlgoNameCosine: func(ap algoParams) (strdist.Algo, error) {
+		if ap.nGramStep > dfltNGramStep || ap.shingleWords {
+			if err := checkNGramStep(ap.nGramStep, ap.nGramLen); err != nil {
+				return nil, err
+			}
+
+			return steppedNGramAlgo{
+				name:      strdist.AlgoNameCosine,
+				length:    ap.nGramLen,
+				step:      ap.nGramStep,
+				wordLevel: ap.shingleWords,
+				index:     strdist.CosineSimilarity,
+			}, nil
+		}
+
+		ngc := strdist.NGramConfig{
+			Length:            ap.nGramLen,
+			MinLength:         ap.minNGramLen,
+			OverFlowTheSource: ap.overflowTheSource,
+		}
+		return strdist.NewCosineAlgo(ngc, ap.maxNGramCacheSize)
+	},
+	strdist.AlgoNameHamming: func(_ algoParams) (strdist.Algo, error) {
+		return strdist.HammingAlgo{}, nil
+	},
+	strdist.AlgoNameJaccard: func(ap algoParams) (strdist.Algo, error) {
+		if ap.nGramStep > dfltNGramStep || ap.shingleWords {
+			if err := checkNGramStep(ap.nGramStep, ap.nGramLen); err != nil {
+				return nil, err
+			}
+
+			return steppedNGramAlgo{
+				name:      strdist.AlgoNameJaccard,
+				length:    ap.nGramLen,
+				step:      ap.nGramStep,
+				wordLevel: ap.shingleWords,
+				index:     strdist.JaccardIndex,
+			}, nil
+		}
+
+		ngc := strdist.NGramConfig{
+			Length:            ap.nGramLen,
+			MinLength:         ap.minNGramLen,
+			OverFlowTheSource: ap.overflowTheSource,
+		}
+		return strdist.NewJaccardAlgo(ngc, ap.maxNGramCacheSize)
+	},
+	strdist.AlgoNameWeightedJaccard: func(ap algoParams) (
+		strdist.Algo, error,
+	) {
+		if ap.nGramStep > dfltNGramStep || ap.shingleWords {
+			if err := checkNGramStep(ap.nGramStep, ap.nGramLen); err != nil {
+				return nil, err
+			}
+
+			return steppedNGramAlgo{
+				name:      strdist.AlgoNameWeightedJaccard,
+				length:    ap.nGramLen,
+				step:      ap.nGramStep,
+				wordLevel: ap.shingleWords,
+				index:     strdist.WeightedJaccardIndex,
+			}, nil
+		}
+
+		ngc := strdist.NGramConfig{
+			Length:            ap.nGramLen,
+			MinLength:         ap.minNGramLen,
+			OverFlowTheSource: ap.overflowTheSource,
+		}
+		return strdist.NewWeightedJaccardAlgo(ngc, ap.maxNGramCacheSize)
+	},
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readAlgoFile reads one algorithm spec per line, in the same
+// name=subval="..." form accepted by --algo, from prog.algoFile and feeds
+// each one through prog.algoSetter, so it populates prog.algoParams
+// exactly as a command-line --algo flag would. Blank lines and lines
+// whose first non-space character is '#' are skipped. Entries loaded this
+// way are appended after any --algo flags already given.
+func (prog *Prog) readAlgoFile() error {
+	f, err := os.Open(prog.algoFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	lineNum := 0
+
+	for s.Scan() {
+		lineNum++
+
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := prog.algoSetter.SetWithVal("", line); err != nil {
+			return fmt.Errorf("%s, line %d: %w", prog.algoFile, lineNum, err)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// auditRecord is a single line of the --audit-file, describing one
+// accepted match: the target, the matched population value, which
+// algorithm found it, the distance and threshold that admitted it, and
+// the preprocessing that was in effect. It exists purely to give a
+// compliance audit trail of every fuzzy-matching decision, independent
+// of whatever report format was chosen for display.
+type auditRecord struct {
+	Target         string  `json:"target"`
+	Matched        string  `json:"matched"`
+	Algorithm      string  `json:"algorithm"`
+	Distance       float64 `json:"distance"`
+	Threshold      float64 `json:"threshold"`
+	MapToLowerCase bool    `json:"mapToLowerCase"`
+	StripRunes     string  `json:"stripRunes"`
+}
+
+// auditHitsFor returns an auditRecord for every entry in sd - every match
+// f accepted for target, before any --max-results truncation. origOf, if
+// non-nil, maps a (possibly transliterated or tokenised) population value
+// back to the original word to report.
+func auditHitsFor(
+	target string, f *strdist.Finder, sd []strdist.StrDist,
+	origOf map[string]string,
+) []auditRecord {
+	hits := make([]auditRecord, 0, len(sd))
+
+	for _, hit := range sd {
+		val := hit.Str
+		if orig, ok := origOf[val]; ok {
+			val = orig
+		}
+
+		hits = append(hits, auditRecord{
+			Target:         target,
+			Matched:        val,
+			Algorithm:      f.Algo.Name(),
+			Distance:       hit.Dist,
+			Threshold:      f.FinderConfig.Threshold,
+			MapToLowerCase: f.FinderConfig.MapToLowerCase,
+			StripRunes:     f.FinderConfig.StripRunes,
+		})
+	}
+
+	return hits
+}
+
+// writeAuditFile writes prog's accumulated auditRecords to the configured
+// --audit-file, if any, one JSON object per line. It is called from a
+// defer in Run so that whatever was matched before an early return is
+// still captured.
+func (prog *Prog) writeAuditFile() {
+	if prog.auditFile == "" {
+		return
+	}
+
+	f, err := os.Create(prog.auditFile)
+	if err != nil {
+		fmt.Println("Couldn't create the audit file:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+
+	for _, r := range prog.auditRecords {
+		if err := enc.Encode(r); err != nil {
+			fmt.Println("Couldn't write the audit file:", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// runBenchmark runs, for each finder in turn, the search across every
+// target repeated prog.benchmarkReps times, discarding the match slices,
+// and reports the elapsed time and throughput. The population and
+// finders are built exactly as for a normal search; only the output and
+// the repeat loop differ. No search report is printed.
+func (prog *Prog) runBenchmark(
+	finders []*strdist.Finder, searchPop, targets []string, find findLikeFunc,
+) {
+	fmt.Fprintln(os.Stdout,
+		"algorithm\tcomparisons\telapsed seconds\tcomparisons/second")
+
+	var (
+		totalComparisons int64
+		totalElapsed     time.Duration
+	)
+
+	for _, f := range finders {
+		comparisons := int64(len(targets)) * int64(len(searchPop)) *
+			int64(prog.benchmarkReps)
+
+		start := time.Now()
+
+		for range prog.benchmarkReps {
+			for _, target := range targets {
+				group := parseTargetGroup(target)
+				if prog.transliterate {
+					group = transliterateGroup(group)
+				}
+
+				if prog.tokenise {
+					group = tokeniseGroup(prog.tokenRegex, group)
+				}
+
+				if prog.graphemeClusters {
+					group = prog.graphemeEnc.encodeGroup(group)
+				}
+
+				_ = findLikeGroup(f, group, searchPop, find)
+			}
+		}
+
+		elapsed := time.Since(start)
+
+		totalComparisons += comparisons
+		totalElapsed += elapsed
+
+		throughput := float64(comparisons) / elapsed.Seconds()
+
+		fmt.Fprintf(os.Stdout, "%s\t%d\t%.4f\t%.0f\n",
+			f.Algo.Name(), comparisons, elapsed.Seconds(), throughput)
+	}
+
+	fmt.Fprintf(os.Stdout, "total\t%d\t%.4f\t%.0f\n",
+		totalComparisons, totalElapsed.Seconds(),
+		float64(totalComparisons)/totalElapsed.Seconds())
+}
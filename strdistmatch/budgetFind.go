@@ -0,0 +1,108 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// budgetCheckInterval is how many population words findLikeWithBudget
+// scores between checks of the elapsed time, so that the cost of reading
+// the clock doesn't dominate the scan for cheap algorithms.
+const budgetCheckInterval = 256
+
+// findLikeWithBudget behaves like f.FindLike(s, pop...), except that once
+// budget has elapsed since the call began it stops scanning pop and
+// returns only the matches found so far, with truncated set to true. It
+// duplicates FindLike's preparation, filtering and ordering logic (see
+// prepStr in trieFind.go) since a scan that can be cut short part-way
+// through isn't something FindLike itself supports. It always applies
+// MinStrLength to both the target and each population word, regardless of
+// any minStrLenAppliesTo setting for f, and it scans pop directly rather
+// than through a trie - --per-algo-budget takes precedence over both
+// --use-trie and minStrLenAppliesTo rather than composing with them.
+func findLikeWithBudget(
+	f *strdist.Finder, s string, pop []string, budget time.Duration,
+) (dists []strdist.StrDist, truncated bool) {
+	if len(pop) == 0 {
+		return nil, false
+	}
+
+	s = prepStr(f.FinderConfig, s)
+	if len(s) < f.FinderConfig.MinStrLength {
+		return nil, false
+	}
+
+	deadline := time.Now().Add(budget)
+	dists = make([]strdist.StrDist, 0, len(pop))
+
+	for i, pOrig := range pop {
+		if i%budgetCheckInterval == 0 && time.Now().After(deadline) {
+			truncated = true
+			break
+		}
+
+		p := prepStr(f.FinderConfig, pOrig)
+		if len(p) < f.FinderConfig.MinStrLength {
+			continue
+		}
+
+		d := f.Algo.Dist(s, p)
+		if d > f.FinderConfig.Threshold {
+			continue
+		}
+
+		dists = append(dists, strdist.StrDist{Str: pOrig, Dist: d})
+	}
+
+	sort.Slice(dists, minStrLenSortLess(dists, len(s)))
+
+	return dists, truncated
+}
+
+// truncationTracker records which (finder, target) searches were cut
+// short by --per-algo-budget, so the report can annotate them. It is
+// written to concurrently, from the goroutines searching each finder for
+// a given target, so access is guarded by a mutex.
+type truncationTracker struct {
+	mu   sync.Mutex
+	hits map[*strdist.Finder]map[string]bool
+}
+
+// newTruncationTracker returns a new, empty truncationTracker.
+func newTruncationTracker() *truncationTracker {
+	return &truncationTracker{hits: map[*strdist.Finder]map[string]bool{}}
+}
+
+// mark records that the search for s under f was truncated.
+func (t *truncationTracker) mark(f *strdist.Finder, s string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hits, ok := t.hits[f]
+	if !ok {
+		hits = map[string]bool{}
+		t.hits[f] = hits
+	}
+
+	hits[s] = true
+}
+
+// any reports whether the search for f was truncated for any of members -
+// the strings making up a target group, since a truncated search for any
+// one member makes the group's merged result for f incomplete.
+func (t *truncationTracker) any(f *strdist.Finder, members []string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	hits := t.hits[f]
+	for _, s := range members {
+		if hits[s] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// commonColumnInfo records, for each configuration column shown by the
+// full (non-compact) report, the value shared by every finder - nil if
+// the finders differ on that column. It is used by --hide-common-columns
+// to omit columns that would otherwise show the same value in every row.
+type commonColumnInfo struct {
+	algoDetails        *string
+	threshold          *float64
+	minStrLength       *int
+	minStrLenAppliesTo *string
+	mapToLowerCase     *bool
+	stripRunes         *string
+}
+
+// computeCommonColumns returns a commonColumnInfo describing which of the
+// full report's configuration columns are identical across every finder
+// in finders. minStrLenModes gives each finder's minStrLenAppliesTo
+// setting, keyed by finder, since that isn't held on FinderConfig itself.
+// If finders is empty, or has only one entry, every field is nil since
+// there is nothing to be redundant with.
+func computeCommonColumns(
+	finders []*strdist.Finder, minStrLenModes map[*strdist.Finder]string,
+) commonColumnInfo {
+	var cc commonColumnInfo
+
+	if len(finders) < 2 { //nolint:mnd
+		return cc
+	}
+
+	details := finders[0].Algo.Desc()
+	threshold := finders[0].FinderConfig.Threshold
+	minStrLen := finders[0].FinderConfig.MinStrLength
+	minStrLenAppliesTo := minStrLenModes[finders[0]]
+	mapToLower := finders[0].FinderConfig.MapToLowerCase
+	stripRunes := finders[0].FinderConfig.StripRunes
+
+	sameDetails, sameThreshold := true, true
+	sameMinStrLen, sameMinStrLenAppliesTo := true, true
+	sameMapToLower, sameStripRunes := true, true
+
+	for _, f := range finders[1:] {
+		if f.Algo.Desc() != details {
+			sameDetails = false
+		}
+
+		if f.FinderConfig.Threshold != threshold {
+			sameThreshold = false
+		}
+
+		if f.FinderConfig.MinStrLength != minStrLen {
+			sameMinStrLen = false
+		}
+
+		if minStrLenModes[f] != minStrLenAppliesTo {
+			sameMinStrLenAppliesTo = false
+		}
+
+		if f.FinderConfig.MapToLowerCase != mapToLower {
+			sameMapToLower = false
+		}
+
+		if f.FinderConfig.StripRunes != stripRunes {
+			sameStripRunes = false
+		}
+	}
+
+	if sameDetails {
+		cc.algoDetails = &details
+	}
+
+	if sameThreshold {
+		cc.threshold = &threshold
+	}
+
+	if sameMinStrLen {
+		cc.minStrLength = &minStrLen
+	}
+
+	if sameMinStrLenAppliesTo {
+		cc.minStrLenAppliesTo = &minStrLenAppliesTo
+	}
+
+	if sameMapToLower {
+		cc.mapToLowerCase = &mapToLower
+	}
+
+	if sameStripRunes {
+		cc.stripRunes = &stripRunes
+	}
+
+	return cc
+}
+
+// commonColumnsFooter returns a footer line summarising the columns
+// hidden because of --hide-common-columns and the value shared by every
+// finder, or the empty string if no columns were hidden.
+func commonColumnsFooter(cc commonColumnInfo) string {
+	parts := []string{}
+
+	if cc.algoDetails != nil {
+		parts = append(parts, fmt.Sprintf("details=%q", *cc.algoDetails))
+	}
+
+	if cc.threshold != nil {
+		parts = append(parts, fmt.Sprintf("threshold=%.5f", *cc.threshold))
+	}
+
+	if cc.minStrLength != nil {
+		parts = append(parts, fmt.Sprintf("min str len=%d", *cc.minStrLength))
+	}
+
+	if cc.minStrLenAppliesTo != nil {
+		parts = append(parts,
+			fmt.Sprintf("min str len applies to=%s", *cc.minStrLenAppliesTo))
+	}
+
+	if cc.mapToLowerCase != nil {
+		parts = append(parts, fmt.Sprintf("map to lower=%t", *cc.mapToLowerCase))
+	}
+
+	if cc.stripRunes != nil {
+		parts = append(parts, fmt.Sprintf("strip runes=%q", *cc.stripRunes))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return "\ncommon values (same for every algorithm, columns hidden): " +
+		strings.Join(parts, ", ") + "\n"
+}
@@ -0,0 +1,28 @@
+package main
+
+import "github.com/nickwells/strdist.mod/v2/strdist"
+
+// confidence returns a score in [0, 1] for how much more confident the
+// best match in sd (sd[0]) is than the runner-up (sd[1]): a large gap
+// between the two distances gives a score close to 1, a near-tie gives
+// a score close to 0. sd must be sorted best-match-first, as FindLike
+// returns it.
+//
+// The score is the normalised gap between the two distances,
+// (d1 - d0) / (d0 + d1), so it is independent of the algorithm's own
+// distance scale. If there is no runner-up to compare against, the
+// score is 1: nothing contradicts the best match. If the best match and
+// the runner-up are tied, the score is 0, however small or large the
+// tied distance is.
+func confidence(sd []strdist.StrDist) float64 {
+	if len(sd) < 2 { //nolint:mnd
+		return 1
+	}
+
+	d0, d1 := sd[0].Dist, sd[1].Dist
+	if d0 == d1 {
+		return 0
+	}
+
+	return (d1 - d0) / (d0 + d1)
+}
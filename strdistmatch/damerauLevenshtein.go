@@ -0,0 +1,92 @@
+package main
+
+import "github.com/nickwells/strdist.mod/v2/strdist"
+
+// AlgoNameDamerauLevenshtein is the algorithm name used for --algo and
+// reported in results. strdist has no implementation of its own, so
+// damerauLevenshteinAlgo below provides one.
+const AlgoNameDamerauLevenshtein = "Damerau-Levenshtein"
+
+// dfltThresholdDamerauLevenshtein mirrors strdist.DfltThresholdLevenshtein:
+// like plain Levenshtein, its Dist is a raw edit count, not a value in
+// [0,1].
+const dfltThresholdDamerauLevenshtein = strdist.DfltThresholdLevenshtein
+
+// localDefaultThresholds holds the default threshold for every algorithm
+// this tool implements itself, for algorithm names that
+// strdist.DefaultThresholds doesn't know about.
+var localDefaultThresholds = map[string]float64{
+	AlgoNameDamerauLevenshtein: dfltThresholdDamerauLevenshtein,
+}
+
+// defaultThresholdFor returns the default similarity threshold for
+// algoName, checking strdist's own algorithms first and falling back to
+// the algorithms this tool adds itself.
+func defaultThresholdFor(algoName string) float64 {
+	if t, ok := strdist.DefaultThresholds[algoName]; ok {
+		return t
+	}
+
+	return localDefaultThresholds[algoName]
+}
+
+// damerauLevenshteinAlgo implements strdist.Algo using the (restricted,
+// a.k.a. optimal string alignment) Damerau-Levenshtein distance:
+// Levenshtein extended to treat the transposition of two adjacent
+// characters as a single edit rather than two, which better reflects the
+// most common typo of swapped letters.
+type damerauLevenshteinAlgo struct{}
+
+// Name returns the algorithm name
+func (damerauLevenshteinAlgo) Name() string { return AlgoNameDamerauLevenshtein }
+
+// Desc returns a string describing the algorithm configuration
+func (damerauLevenshteinAlgo) Desc() string { return "" }
+
+// Dist for a damerauLevenshteinAlgo will calculate the restricted
+// Damerau-Levenshtein distance between the two strings
+func (damerauLevenshteinAlgo) Dist(s1, s2 string) float64 {
+	return float64(damerauLevenshteinDistance(s1, s2))
+}
+
+// damerauLevenshteinDistance calculates the restricted Damerau-Levenshtein
+// distance between a and b: the minimum number of insertions, deletions,
+// substitutions and adjacent transpositions needed to turn a into b. It
+// operates on runes so that multi-byte characters each count as a single
+// edit.
+//
+//nolint:mnd
+func damerauLevenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	la, lb := len(ra), len(rb)
+
+	d := make([][]int, la+1)
+	for i := range d {
+		d[i] = make([]int, lb+1)
+		d[i][0] = i
+	}
+
+	for j := 0; j <= lb; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= la; i++ {
+		for j := 1; j <= lb; j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			best := min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 &&
+				ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				best = min(best, d[i-2][j-2]+cost)
+			}
+
+			d[i][j] = best
+		}
+	}
+
+	return d[la][lb]
+}
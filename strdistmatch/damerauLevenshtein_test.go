@@ -0,0 +1,92 @@
+package main
+
+import "testing"
+
+func TestDamerauLevenshteinDistance(t *testing.T) {
+	testCases := []struct {
+		name   string
+		a, b   string
+		expVal int
+	}{
+		{
+			name:   "identical strings",
+			a:      "kitten",
+			b:      "kitten",
+			expVal: 0,
+		},
+		{
+			name:   "empty vs non-empty",
+			a:      "",
+			b:      "abc",
+			expVal: 3,
+		},
+		{
+			name:   "single substitution",
+			a:      "kitten",
+			b:      "kitteo",
+			expVal: 1,
+		},
+		{
+			name:   "single insertion",
+			a:      "kitten",
+			b:      "kittens",
+			expVal: 1,
+		},
+		{
+			name:   "adjacent transposition counts as one edit",
+			a:      "ab",
+			b:      "ba",
+			expVal: 1,
+		},
+		{
+			name:   "transposition beats two substitutions",
+			a:      "converse",
+			b:      "convesre",
+			expVal: 1,
+		},
+		{
+			name:   "classic kitten/sitting example",
+			a:      "kitten",
+			b:      "sitting",
+			expVal: 3,
+		},
+		{
+			name:   "multibyte runes count as single edits",
+			a:      "café",
+			b:      "cafe",
+			expVal: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		got := damerauLevenshteinDistance(tc.a, tc.b)
+		if got != tc.expVal {
+			t.Errorf("%s: damerauLevenshteinDistance(%q, %q) == %d, want %d",
+				tc.name, tc.a, tc.b, got, tc.expVal)
+		}
+
+		gotReversed := damerauLevenshteinDistance(tc.b, tc.a)
+		if gotReversed != tc.expVal {
+			t.Errorf("%s: damerauLevenshteinDistance(%q, %q) == %d, want %d"+
+				" (distance should be symmetric)",
+				tc.name, tc.b, tc.a, gotReversed, tc.expVal)
+		}
+	}
+}
+
+func TestDamerauLevenshteinAlgo(t *testing.T) {
+	algo := damerauLevenshteinAlgo{}
+
+	if got := algo.Name(); got != AlgoNameDamerauLevenshtein {
+		t.Errorf("Name() == %q, want %q", got, AlgoNameDamerauLevenshtein)
+	}
+
+	const s1, s2 = "ab", "ba"
+
+	got := algo.Dist(s1, s2)
+
+	const expVal = 1
+	if got != expVal {
+		t.Errorf("Dist(%q, %q) == %g, want %g", s1, s2, got, float64(expVal))
+	}
+}
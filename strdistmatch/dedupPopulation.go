@@ -0,0 +1,21 @@
+package main
+
+// dedupPopulation removes duplicate entries from pop, preserving the
+// order of first occurrence, and returns the deduplicated slice along
+// with the number of entries dropped.
+func dedupPopulation(pop []string) ([]string, int) {
+	seen := make(map[string]bool, len(pop))
+	deduped := make([]string, 0, len(pop))
+
+	for _, w := range pop {
+		if seen[w] {
+			continue
+		}
+
+		seen[w] = true
+
+		deduped = append(deduped, w)
+	}
+
+	return deduped, len(pop) - len(deduped)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// reportEquivalentFinders computes, for every target, each finder's top
+// --max-results matches (value and distance, in order) and reports any
+// pair of finders whose results are identical for every target,
+// suggesting that one of the pair is redundant for this population and
+// this set of targets. No search report is printed.
+func (prog *Prog) reportEquivalentFinders(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	signatures := make([][]string, len(finders))
+	for i := range signatures {
+		signatures[i] = make([]string, len(targets))
+	}
+
+	for ti, target := range targets {
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		for fi, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, origOf)
+			}
+
+			signatures[fi][ti] = resultSignature(sd, origOf, prog.maxResults)
+		}
+	}
+
+	found := false
+
+	for i := range finders {
+		for j := i + 1; j < len(finders); j++ {
+			if !slices.Equal(signatures[i], signatures[j]) {
+				continue
+			}
+
+			found = true
+
+			fmt.Printf(
+				"%q (entry %d) and %q (entry %d) give identical results"+
+					" for every target\n",
+				finders[i].Algo.Name(), i+1,
+				finders[j].Algo.Name(), j+1)
+		}
+	}
+
+	if !found {
+		fmt.Println("no two algorithms gave identical results")
+	}
+}
+
+// resultSignature returns a string uniquely representing the top
+// maxResults entries of sd - value and distance, in order - so that two
+// result lists can be compared for equality with a simple string
+// comparison.
+func resultSignature(
+	sd []strdist.StrDist, origOf map[string]string, maxResults int,
+) string {
+	var b strings.Builder
+
+	for i := range maxResults {
+		if i >= len(sd) {
+			break
+		}
+
+		val := sd[i].Str
+		if orig, ok := origOf[val]; ok {
+			val = orig
+		}
+
+		fmt.Fprintf(&b, "%s:%.6f|", val, sd[i].Dist)
+	}
+
+	return b.String()
+}
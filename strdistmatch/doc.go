@@ -0,0 +1,5 @@
+/*
+strdistmatch will report matches for a string from a file. You can specify
+which string distance algorithm to use.
+*/
+package main
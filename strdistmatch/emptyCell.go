@@ -0,0 +1,31 @@
+package main
+
+import "github.com/nickwells/col.mod/v4/col"
+
+// emptyCellFormatter wraps a col.Formatter so that a nil value - which the
+// wrapped formatter would otherwise render as a blank cell - is rendered as
+// a fixed placeholder string instead.
+type emptyCellFormatter struct {
+	col.Formatter
+	placeholder string
+}
+
+// Formatted returns the placeholder for a nil value, and otherwise defers
+// to the wrapped Formatter.
+func (f emptyCellFormatter) Formatted(v any) string {
+	if v == nil {
+		return f.placeholder
+	}
+
+	return f.Formatter.Formatted(v)
+}
+
+// withEmptyCell wraps f in an emptyCellFormatter if placeholder is set, and
+// otherwise returns f unchanged.
+func withEmptyCell(f col.Formatter, placeholder string) col.Formatter {
+	if placeholder == "" {
+		return f
+	}
+
+	return emptyCellFormatter{Formatter: f, placeholder: placeholder}
+}
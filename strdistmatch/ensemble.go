@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// ensembleScore normalizes sd's distances to [0,1] by min-max scaling
+// across sd itself, and returns them keyed by matched string. A finder
+// with only one result, or with all results at the same distance, scores
+// its sole distance value as 0 - there is nothing to scale against.
+func ensembleScore(sd []strdist.StrDist) map[string]float64 {
+	scores := make(map[string]float64, len(sd))
+
+	if len(sd) == 0 {
+		return scores
+	}
+
+	minDist, maxDist := sd[0].Dist, sd[0].Dist
+
+	for _, s := range sd {
+		if s.Dist < minDist {
+			minDist = s.Dist
+		}
+
+		if s.Dist > maxDist {
+			maxDist = s.Dist
+		}
+	}
+
+	spread := maxDist - minDist
+
+	for _, s := range sd {
+		if spread == 0 {
+			scores[s.Str] = 0
+
+			continue
+		}
+
+		scores[s.Str] = (s.Dist - minDist) / spread
+	}
+
+	return scores
+}
+
+// writeEnsemble computes, for each target, a single fused ranking across
+// all finders: each finder's distances are normalized to [0,1], a
+// candidate string not returned by a finder scores 1 (worst-case) for
+// that finder, and the fused score is the mean across finders. The
+// fused-best maxResults candidates are printed as tab-separated
+// (target, rank, fused score, value) lines to stdout; the individual
+// per-finder blocks are suppressed in this mode.
+func (prog *Prog) writeEnsemble(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	fmt.Fprintln(os.Stdout, "target\trank\tfused score\tvalue")
+
+	for _, target := range targets {
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		perFinderScores := make([]map[string]float64, len(finders))
+		candidates := map[string]bool{}
+
+		for i, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+			perFinderScores[i] = ensembleScore(sd)
+
+			for s := range perFinderScores[i] {
+				candidates[s] = true
+			}
+		}
+
+		fused := make(map[string]float64, len(candidates))
+
+		for c := range candidates {
+			total := 0.0
+
+			for _, scores := range perFinderScores {
+				score, ok := scores[c]
+				if !ok {
+					score = 1
+				}
+
+				total += score
+			}
+
+			fused[c] = total / float64(len(finders))
+		}
+
+		ranked := make([]string, 0, len(fused))
+		for c := range fused {
+			ranked = append(ranked, c)
+		}
+
+		sort.Slice(ranked, func(i, j int) bool {
+			if fused[ranked[i]] != fused[ranked[j]] {
+				return fused[ranked[i]] < fused[ranked[j]]
+			}
+
+			return ranked[i] < ranked[j]
+		})
+
+		for i := range prog.maxResults {
+			if i >= len(ranked) {
+				break
+			}
+
+			val := ranked[i]
+			if orig, ok := origOf[val]; ok {
+				val = orig
+			}
+
+			fmt.Fprintf(os.Stdout, "%s\t%d\t%.4f\t%s\n",
+				target, i+1, fused[ranked[i]], val)
+		}
+	}
+}
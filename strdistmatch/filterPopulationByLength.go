@@ -0,0 +1,26 @@
+package main
+
+// filterPopulationByLength drops entries from pop whose rune length falls
+// outside [prog.popMinLen, prog.popMaxLen], counting runes rather than
+// bytes so multibyte words aren't mis-measured. A zero bound is treated as
+// unset on that side. It returns the filtered slice and the number of
+// entries dropped.
+func (prog *Prog) filterPopulationByLength(pop []string) ([]string, int) {
+	filtered := make([]string, 0, len(pop))
+
+	for _, w := range pop {
+		n := len([]rune(w))
+
+		if prog.popMinLen > 0 && n < prog.popMinLen {
+			continue
+		}
+
+		if prog.popMaxLen > 0 && n > prog.popMaxLen {
+			continue
+		}
+
+		filtered = append(filtered, w)
+	}
+
+	return filtered, len(pop) - len(filtered)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// flatRow is one (target, algorithm, distance, match) tuple as emitted by
+// --flat-sorted.
+type flatRow struct {
+	Target   string
+	Algo     string
+	Distance float64
+	Match    string
+}
+
+// writeFlatSorted computes every (target, algorithm, distance, match)
+// tuple, up to prog.maxResults per target/algorithm pair, and writes them
+// to stdout as tab-separated lines sorted by (target, algorithm, distance,
+// match). Distances are formatted with fixed precision so that the sort
+// order, and so the output, is stable between runs. This is meant for
+// regression comparison across tool versions: two runs against the same
+// inputs can simply be diffed.
+func (prog *Prog) writeFlatSorted(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	rows := []flatRow{}
+
+	for _, target := range targets {
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		for _, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, origOf)
+			}
+
+			for i := range prog.maxResults {
+				if i >= len(sd) {
+					break
+				}
+
+				val := sd[i].Str
+				if orig, ok := origOf[val]; ok {
+					val = orig
+				}
+
+				rows = append(rows, flatRow{
+					Target:   target,
+					Algo:     f.Algo.Name(),
+					Distance: sd[i].Dist,
+					Match:    val,
+				})
+			}
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Target != rows[j].Target {
+			return rows[i].Target < rows[j].Target
+		}
+
+		if rows[i].Algo != rows[j].Algo {
+			return rows[i].Algo < rows[j].Algo
+		}
+
+		if rows[i].Distance != rows[j].Distance {
+			return rows[i].Distance < rows[j].Distance
+		}
+
+		return rows[i].Match < rows[j].Match
+	})
+
+	for _, r := range rows {
+		fmt.Fprintf(os.Stdout, "%s\t%s\t%.4f\t%s\n",
+			r.Target, r.Algo, r.Distance, r.Match)
+	}
+}
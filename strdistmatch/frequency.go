@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// getWordsWithFrequency reads r as CSV, taking the first field of each
+// record as the population word and the field at prog.frequencyColumn (a
+// 1-based column index) as its frequency. It populates prog.frequencies as
+// a side effect, merging into any entries already read from an earlier
+// word file. wordFile is only used to identify the file in error
+// messages. It will exit on any error.
+func (prog *Prog) getWordsWithFrequency(r io.Reader, wordFile string) []string {
+	if prog.frequencies == nil {
+		prog.frequencies = map[string]float64{}
+	}
+
+	pop := []string{}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			fmt.Println("Reading the file of words to search ("+wordFile+"):", err)
+			prog.SetExitStatus(1)
+
+			return nil
+		}
+
+		if len(rec) == 0 {
+			continue
+		}
+
+		word := rec[0]
+		pop = append(pop, word)
+
+		if prog.frequencyColumn > len(rec) {
+			fmt.Printf(
+				"The record for %q has no column %d to take a"+
+					" frequency from\n",
+				word, prog.frequencyColumn)
+			prog.SetExitStatus(1)
+
+			return nil
+		}
+
+		freq, err := strconv.ParseFloat(rec[prog.frequencyColumn-1], 64)
+		if err != nil {
+			fmt.Printf("Bad frequency for %q: %s\n", word, err)
+			prog.SetExitStatus(1)
+
+			return nil
+		}
+
+		prog.frequencies[word] = freq
+	}
+
+	if len(pop) == 0 {
+		fmt.Println("The file of words to search", wordFile, "is empty")
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+
+	return pop
+}
+
+// frequencyOf returns the frequency recorded for s, mapping it back through
+// origOf first if s is a transliterated population value.
+func (prog *Prog) frequencyOf(s string, origOf map[string]string) float64 {
+	if orig, ok := origOf[s]; ok {
+		s = orig
+	}
+
+	return prog.frequencies[s]
+}
+
+// sortByFrequency stably re-sorts sd, which is assumed to already be sorted
+// by ascending distance, so that within any group of equal-distance results
+// the more frequent population words come first. It only reorders
+// equal-distance results; it never lets a more frequent but worse-matching
+// word take precedence over a closer one.
+func (prog *Prog) sortByFrequency(sd []strdist.StrDist, origOf map[string]string) {
+	sort.SliceStable(sd, func(i, j int) bool {
+		if sd[i].Dist != sd[j].Dist {
+			return sd[i].Dist < sd[j].Dist
+		}
+
+		return prog.frequencyOf(sd[i].Str, origOf) >
+			prog.frequencyOf(sd[j].Str, origOf)
+	})
+}
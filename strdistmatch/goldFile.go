@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+)
+
+// readGoldFile reads the CSV file at prog.goldFile, each record being a
+// target and its single expected correct match (target,expectedMatch), and
+// returns it as a map from target to expected match. It will exit on any
+// error.
+func (prog *Prog) readGoldFile() map[string]string {
+	f, err := os.Open(prog.goldFile)
+	if err != nil {
+		fmt.Println("Failed to open the gold file:", err)
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+	defer f.Close()
+
+	gold := map[string]string{}
+
+	cr := csv.NewReader(f)
+	cr.FieldsPerRecord = -1
+
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			fmt.Println("Reading the gold file:", err)
+			prog.SetExitStatus(1)
+
+			return nil
+		}
+
+		if len(rec) < 2 {
+			fmt.Printf(
+				"The gold file record %q needs a target and its"+
+					" expected match\n", rec)
+			prog.SetExitStatus(1)
+
+			return nil
+		}
+
+		gold[rec[0]] = rec[1]
+	}
+
+	if len(gold) == 0 {
+		fmt.Println("The gold file", prog.goldFile, "is empty")
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+
+	return gold
+}
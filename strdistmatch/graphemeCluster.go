@@ -0,0 +1,168 @@
+package main
+
+import "golang.org/x/text/unicode/norm"
+
+// graphemeClusterPlaceholders is the pool of code points graphemeEncoder
+// draws on to stand in for a multi-rune grapheme cluster: a base rune
+// together with the combining marks that attach to it. It is the C0
+// control range, skipping tab, newline and carriage return since those
+// are given special treatment elsewhere (--tokenise's default splitting
+// pattern, for one) - values no real target or population word should
+// ever contain. A single-rune cluster is already one comparison unit as
+// far as the rune-based algorithms are concerned, so it is left as it
+// is rather than drawn from this pool; only genuine combining sequences
+// need to be collapsed.
+var graphemeClusterPlaceholders = buildGraphemeClusterPlaceholders()
+
+func buildGraphemeClusterPlaceholders() []rune {
+	placeholders := make([]rune, 0, 0x1f) //nolint:mnd
+
+	for r := rune(0x01); r <= 0x1f; r++ {
+		switch r {
+		case '\t', '\n', '\r':
+			continue
+		}
+
+		placeholders = append(placeholders, r)
+	}
+
+	return placeholders
+}
+
+// graphemeEncoder rewrites strings so that each multi-rune grapheme
+// cluster - a base rune together with any combining marks that attach
+// to it, such as an accent or an emoji modifier - is replaced by a
+// single rune of its own. This lets the rune-based algorithms (Hamming,
+// Levenshtein) compare clusters as whole units instead of counting each
+// combining mark as a separate edit. A single-rune cluster, including
+// an already-precomposed accented letter, is left untouched since it is
+// already one comparison unit. A graphemeEncoder must be shared across
+// every string encoded in a single run, so that the same cluster always
+// maps to the same placeholder.
+type graphemeEncoder struct {
+	runeOf map[string]rune
+	next   int
+}
+
+// newGraphemeEncoder returns a graphemeEncoder ready for use.
+func newGraphemeEncoder() *graphemeEncoder {
+	return &graphemeEncoder{
+		runeOf: map[string]rune{},
+	}
+}
+
+// graphemeClusters splits s into grapheme clusters, using Unicode
+// normalisation boundaries to decide where one cluster ends and the
+// next begins: a combining mark is never a boundary, so it stays
+// attached to the base rune before it.
+func graphemeClusters(s string) []string {
+	clusters := make([]string, 0, len(s))
+
+	for len(s) > 0 {
+		i := norm.NFC.NextBoundaryInString(s, true)
+		if i <= 0 {
+			i = len(s)
+		}
+
+		clusters = append(clusters, s[:i])
+		s = s[i:]
+	}
+
+	return clusters
+}
+
+// encode returns s with each multi-rune grapheme cluster replaced by
+// the single placeholder rune enc has assigned to it, assigning the
+// next one available the first time such a cluster is seen. A
+// single-rune cluster is returned unchanged.
+func (enc *graphemeEncoder) encode(s string) string {
+	clusters := graphemeClusters(s)
+
+	runes := make([]rune, len(clusters))
+	for i, c := range clusters {
+		runes[i] = enc.runeFor(c)
+	}
+
+	return string(runes)
+}
+
+// runeFor returns the rune standing in for cluster: cluster itself, if
+// it is already a single rune, or a placeholder shared by every
+// occurrence of that same multi-rune cluster otherwise. Once every
+// placeholder has been handed out, later distinct clusters share the
+// last one - a limitation acceptable for the modest number of distinct
+// combining sequences a real population is likely to contain.
+func (enc *graphemeEncoder) runeFor(cluster string) rune {
+	runes := []rune(cluster)
+	if len(runes) == 1 {
+		return runes[0]
+	}
+
+	if r, ok := enc.runeOf[cluster]; ok {
+		return r
+	}
+
+	r := graphemeClusterPlaceholders[enc.next]
+	enc.runeOf[cluster] = r
+
+	if enc.next < len(graphemeClusterPlaceholders)-1 {
+		enc.next++
+	}
+
+	return r
+}
+
+// encodeGroup returns a copy of group with each member encoded.
+func (enc *graphemeEncoder) encodeGroup(group []string) []string {
+	eGroup := make([]string, len(group))
+
+	for i, m := range group {
+		eGroup[i] = enc.encode(m)
+	}
+
+	return eGroup
+}
+
+// encodePop returns a copy of pop with every word encoded for
+// comparison purposes, along with a map from each encoded form back to
+// the original word it came from. If two population words encode to
+// the same form the later one wins.
+func (enc *graphemeEncoder) encodePop(pop []string) ([]string, map[string]string) {
+	ePop := make([]string, len(pop))
+	origOf := make(map[string]string, len(pop))
+
+	for i, w := range pop {
+		e := enc.encode(w)
+		ePop[i] = e
+		origOf[e] = w
+	}
+
+	return ePop, origOf
+}
+
+// encodeWords encodes pop and returns the encoded population along with
+// an origOf map from encoded form back to the true original word,
+// composing with any origOf map already produced by an earlier
+// transformation (such as --transliterate or --tokenise) so the
+// reported value is always the untransformed original.
+func (enc *graphemeEncoder) encodeWords(
+	pop []string, origOf map[string]string,
+) ([]string, map[string]string) {
+	ePop, eOrigOf := enc.encodePop(pop)
+
+	if origOf == nil {
+		return ePop, eOrigOf
+	}
+
+	composed := make(map[string]string, len(eOrigOf))
+
+	for e, w := range eOrigOf {
+		if orig, ok := origOf[w]; ok {
+			composed[e] = orig
+		} else {
+			composed[e] = w
+		}
+	}
+
+	return ePop, composed
+}
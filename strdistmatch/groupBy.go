@@ -0,0 +1,65 @@
+package main
+
+import "github.com/nickwells/col.mod/v4/col"
+
+// groupBy names how --group-by organises the rows of the main tabular
+// report: by target (the default, today's layout) or by algorithm.
+type groupBy string
+
+const (
+	groupByTarget    groupBy = "target"
+	groupByAlgorithm groupBy = "algorithm"
+)
+
+// printRowsByTarget prints res's rows target-major: the target names the
+// block and is shown once, on the first row, with the finders it was
+// scored against following underneath it, one per row.
+func printRowsByTarget(rpt *col.Report, res targetResult) error {
+	for i, vals := range res.rows {
+		rowVals := append([]any{col.Skip{}}, vals...)
+		if i == 0 {
+			rowVals[0] = res.target
+		}
+
+		if err := rpt.PrintRow(rowVals...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printRowsByAlgorithm prints results algorithm-major: for each finder, in
+// turn, every target's row against it, so that tuning a single algorithm
+// means reading one contiguous block rather than picking its row out from
+// under every target. The finder's name (results[*].rows[fi][0]) names the
+// block and is shown once, on the first row, mirroring how the target
+// itself is collapsed in printRowsByTarget's target-major layout. It
+// assumes every targetResult in results holds a row for the same finders,
+// in the same order, as produced by a single computeTargetResults call.
+// It relies on showColumn forcing reportColumnName on whenever prog's
+// groupBy is groupByAlgorithm, so that rows[fi][0] is always the name
+// column and never a --columns-selected data column that could be
+// mistaken for it.
+func printRowsByAlgorithm(rpt *col.Report, results []targetResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	numFinders := len(results[0].rows)
+
+	for fi := 0; fi < numFinders; fi++ {
+		for ti, res := range results {
+			rowVals := append([]any{res.target}, res.rows[fi]...)
+			if ti > 0 {
+				rowVals[1] = col.Skip{}
+			}
+
+			if err := rpt.PrintRow(rowVals...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// writeGroupedByDistance computes, for each target and finder, the count
+// of matches at each exact distance value found (subject to the finder's
+// threshold and any --min-distance floor), and writes them as
+// tab-separated (target, algorithm, distance, count) lines to stdout,
+// sorted by (target, algorithm, distance). This is a compact summary of
+// how matches cluster by distance, useful when choosing a cutoff
+// threshold.
+func (prog *Prog) writeGroupedByDistance(
+	finders []*strdist.Finder, searchPop, targets []string, find findLikeFunc,
+) {
+	for _, target := range targets {
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		for _, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+
+			counts := map[float64]int{}
+			for _, s := range sd {
+				counts[s.Dist]++
+			}
+
+			distances := make([]float64, 0, len(counts))
+			for d := range counts {
+				distances = append(distances, d)
+			}
+
+			sort.Float64s(distances)
+
+			for _, d := range distances {
+				fmt.Fprintf(os.Stdout, "%s\t%s\t%.4f\t%d\n",
+					target, f.Algo.Name(), d, counts[d])
+			}
+		}
+	}
+}
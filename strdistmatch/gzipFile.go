@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// gzipMagic is the two-byte header every gzip stream starts with.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// gzipReadCloser wraps a gzip.Reader together with the underlying file so
+// that closing it closes both.
+type gzipReadCloser struct {
+	*gzip.Reader
+	f *os.File
+}
+
+// Close closes the gzip.Reader and then the underlying file.
+func (g gzipReadCloser) Close() error {
+	gzErr := g.Reader.Close()
+	fErr := g.f.Close()
+
+	if gzErr != nil {
+		return gzErr
+	}
+
+	return fErr
+}
+
+// plainReadCloser pairs a reader, possibly buffered ahead of the
+// underlying file to sniff its gzip magic, with the file to close.
+type plainReadCloser struct {
+	io.Reader
+	f *os.File
+}
+
+// Close closes the underlying file.
+func (p plainReadCloser) Close() error {
+	return p.f.Close()
+}
+
+// openWordFile opens path and returns a ReadCloser over its contents,
+// transparently decompressing it if it is gzipped. A gzipped file is
+// recognised by a ".gz" suffix or by its magic bytes, so a gzipped file
+// without the conventional suffix is still handled correctly. A corrupt
+// gzip stream is reported as an error rather than being surfaced later as
+// garbled population words.
+func openWordFile(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s is not a valid gzip file: %w", path, err)
+		}
+
+		return gzipReadCloser{Reader: gz, f: f}, nil
+	}
+
+	br := bufio.NewReader(f)
+
+	magic, err := br.Peek(len(gzipMagic))
+	if err == nil && string(magic) == string(gzipMagic) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s is not a valid gzip file: %w", path, err)
+		}
+
+		return gzipReadCloser{Reader: gz, f: f}, nil
+	}
+
+	return plainReadCloser{Reader: br, f: f}, nil
+}
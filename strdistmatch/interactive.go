@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// runInteractive implements --interactive: with the finders and
+// population already built, it reads query strings one per line from
+// stdin and prints the result table for each until EOF, so that the
+// expensive population and finder setup stays resident across queries
+// instead of being rebuilt for every one. Blank lines are ignored; EOF
+// (Ctrl-D) ends the loop and lets Run return normally, with exit status 0
+// unless a query along the way set it otherwise.
+func (prog *Prog) runInteractive(
+	finders []*strdist.Finder, searchPop []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	maxResultValueLen := getMaxStrLen(searchPop)
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		query := scanner.Text()
+		if query == "" {
+			continue
+		}
+
+		rpt := prog.makeReport(finders, []string{query}, maxResultValueLen)
+		if rpt == nil {
+			return
+		}
+
+		res := prog.computeTargetResult(
+			query, finders, searchPop, origOf, find, prog.maxConcurrentAlgos)
+
+		if err := printRowsByTarget(rpt, res); err != nil {
+			fmt.Printf("Cannot print the report: %s\n", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Println("Error reading queries:", err)
+		prog.SetExitStatus(1)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// TestRunInteractive feeds runInteractive a couple of queries (with a
+// blank line to be ignored) over a pipe standing in for stdin, and checks
+// that a report is printed for each non-blank query.
+func TestRunInteractive(t *testing.T) {
+	pop := []string{"apple", "apply", "banana"}
+
+	prog := NewProg()
+	prog.algoParams = []NamedValue[string, algoParams]{
+		{Name: strdist.AlgoNameLevenshtein, Value: algoParams{}},
+	}
+
+	finders := prog.makeFinders(pop)
+	if prog.exitStatus != 0 {
+		t.Fatalf("makeFinders set a non-zero exit status")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("couldn't create the stdin pipe: %s", err)
+	}
+
+	origStdin := os.Stdin
+	os.Stdin = r
+
+	defer func() { os.Stdin = origStdin }()
+
+	go func() {
+		defer w.Close()
+		w.WriteString("apple\n\nappl\n")
+	}()
+
+	var buf bytes.Buffer
+	prog.outputWriter = &buf
+
+	prog.runInteractive(finders, pop, nil, findLike)
+
+	if prog.exitStatus != 0 {
+		t.Errorf("runInteractive set a non-zero exit status")
+	}
+
+	out := buf.String()
+	if got := strings.Count(out, strdist.AlgoNameLevenshtein); got != 2 {
+		t.Errorf("expected two reports (one per query), got %d in:\n%s",
+			got, out)
+	}
+}
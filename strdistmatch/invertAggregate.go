@@ -0,0 +1,58 @@
+package main
+
+import "github.com/nickwells/strdist.mod/v2/strdist"
+
+// invertAggregate names how the distances for a population word that
+// matches several targets should be combined into the single score
+// --invert-aggregate reports for that word, in the --invert report.
+type invertAggregate string
+
+const (
+	invertAggregateMin  invertAggregate = "min"
+	invertAggregateMean invertAggregate = "mean"
+	invertAggregateMax  invertAggregate = "max"
+)
+
+// aggregate combines dists according to agg. It panics if dists is empty
+// or agg is not one of the invertAggregate constants; callers should have
+// already checked that a word has at least one match before aggregating,
+// and psetter.Enum guarantees agg can only hold a value in AllowedVals.
+func (agg invertAggregate) aggregate(dists []strdist.StrDist) float64 {
+	if len(dists) == 0 {
+		panic("invertAggregate.aggregate called with no distances")
+	}
+
+	switch agg {
+	case invertAggregateMin:
+		best := dists[0].Dist
+
+		for _, d := range dists[1:] {
+			if d.Dist < best {
+				best = d.Dist
+			}
+		}
+
+		return best
+	case invertAggregateMax:
+		worst := dists[0].Dist
+
+		for _, d := range dists[1:] {
+			if d.Dist > worst {
+				worst = d.Dist
+			}
+		}
+
+		return worst
+	case invertAggregateMean:
+		sum := 0.0
+
+		for _, d := range dists {
+			sum += d.Dist
+		}
+
+		return sum / float64(len(dists))
+	}
+
+	panic("invertAggregate.aggregate called with an unrecognised value: " +
+		string(agg))
+}
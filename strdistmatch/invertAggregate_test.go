@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestInvertAggregateAggregate(t *testing.T) {
+	dists := []strdist.StrDist{
+		{Str: "a", Dist: 3},
+		{Str: "b", Dist: 1},
+		{Str: "c", Dist: 5},
+	}
+
+	testCases := []struct {
+		testhelper.ID
+		agg    invertAggregate
+		expVal float64
+	}{
+		{
+			ID:     testhelper.MkID("min"),
+			agg:    invertAggregateMin,
+			expVal: 1,
+		},
+		{
+			ID:     testhelper.MkID("max"),
+			agg:    invertAggregateMax,
+			expVal: 5,
+		},
+		{
+			ID:     testhelper.MkID("mean"),
+			agg:    invertAggregateMean,
+			expVal: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		got := tc.agg.aggregate(dists)
+		if got != tc.expVal {
+			t.Errorf("%s: aggregate(%v) == %g, want %g",
+				tc.IDStr(), dists, got, tc.expVal)
+		}
+	}
+}
+
+func TestInvertAggregateAggregatePanics(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		agg   invertAggregate
+		dists []strdist.StrDist
+	}{
+		{
+			ID:  testhelper.MkID("no distances"),
+			agg: invertAggregateMin,
+		},
+		{
+			ID:    testhelper.MkID("unrecognised aggregate"),
+			agg:   invertAggregate("bogus"),
+			dists: []strdist.StrDist{{Str: "a", Dist: 1}},
+		},
+	}
+
+	for _, tc := range testCases {
+		panicked, panicVal := testhelper.PanicSafe(func() {
+			tc.agg.aggregate(tc.dists)
+		})
+		testhelper.PanicCheckString(t, tc.IDStr(),
+			panicked, true,
+			panicVal, []string{"aggregate"})
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// invertedWord holds one population word's matches against a single
+// finder, gathered across every target in a --invert report, together
+// with the single score --invert-aggregate combines them into.
+type invertedWord struct {
+	word    string
+	dists   []strdist.StrDist
+	score   float64
+	matched int
+}
+
+// writeInvertedReport writes the --invert report to stdout: instead of
+// one row per target showing its best-matching population words, one row
+// per population word that matched at least one target, showing how many
+// targets it matched under that finder and prog.invertAggregate's
+// combination of the distances to them. This surfaces population entries
+// that are broadly confusable across many targets, rather than closely
+// tied to just one, which the usual target-major report has no way to
+// show since it never accumulates a word's matches across targets.
+func (prog *Prog) writeInvertedReport(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	_ = w.Write([]string{"algorithm", "word", "matched_targets", "score"})
+
+	for _, f := range finders {
+		words := prog.invertedWordsFor(f, searchPop, targets, origOf, find)
+
+		for _, iw := range words {
+			_ = w.Write([]string{
+				f.Algo.Name(),
+				iw.word,
+				strconv.Itoa(iw.matched),
+				strconv.FormatFloat(iw.score, 'g', -1, 64),
+			})
+		}
+	}
+
+	if err := w.Error(); err != nil {
+		fmt.Println("Couldn't write the inverted report:", err)
+		prog.SetExitStatus(1)
+	}
+}
+
+// invertedWordsFor computes f's invertedWord entries, one per population
+// word matched against any target, sorted by ascending score (and then by
+// word, for a stable order) so the most broadly-confusable words - the
+// ones a min or mean aggregate ranks lowest - lead the report.
+func (prog *Prog) invertedWordsFor(
+	f *strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) []invertedWord {
+	byWord := map[string]*invertedWord{}
+	order := []string{}
+
+	for _, target := range targets {
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+
+		for _, d := range sd {
+			word := d.Str
+			if orig, ok := origOf[word]; ok {
+				word = orig
+			}
+
+			iw, ok := byWord[word]
+			if !ok {
+				iw = &invertedWord{word: word}
+				byWord[word] = iw
+				order = append(order, word)
+			}
+
+			iw.dists = append(iw.dists, d)
+			iw.matched++
+		}
+	}
+
+	words := make([]invertedWord, 0, len(order))
+
+	for _, word := range order {
+		iw := byWord[word]
+		iw.score = prog.invertAggregate.aggregate(iw.dists)
+		words = append(words, *iw)
+	}
+
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].score != words[j].score {
+			return words[i].score < words[j].score
+		}
+
+		return words[i].word < words[j].word
+	})
+
+	return words
+}
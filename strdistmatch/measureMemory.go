@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// measureMemoryUsage runs, for each finder in turn, the search across
+// every target and reports the memory it allocated while doing so:
+// runtime.MemStats is sampled before and after each algorithm's full run,
+// with a forced GC before each sample to reduce cross-algorithm
+// attribution error. Go's garbage collector makes precise attribution
+// impossible, so the figures are approximate - useful for comparing the
+// n-gram algorithms, which cache their n-grams, against the lightweight
+// ones, not as an exact accounting. No search report is printed.
+func (prog *Prog) measureMemoryUsage(
+	finders []*strdist.Finder, searchPop, targets []string, find findLikeFunc,
+) {
+	fmt.Fprintln(os.Stdout, "algorithm\tallocated bytes\tallocations")
+
+	for _, f := range finders {
+		runtime.GC()
+
+		var before, after runtime.MemStats
+
+		runtime.ReadMemStats(&before)
+
+		for _, target := range targets {
+			group := parseTargetGroup(target)
+			if prog.transliterate {
+				group = transliterateGroup(group)
+			}
+
+			if prog.tokenise {
+				group = tokeniseGroup(prog.tokenRegex, group)
+			}
+
+			if prog.graphemeClusters {
+				group = prog.graphemeEnc.encodeGroup(group)
+			}
+
+			_ = prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+		}
+
+		runtime.ReadMemStats(&after)
+
+		fmt.Fprintf(os.Stdout, "%s\t%d\t%d\n",
+			f.Algo.Name(),
+			after.TotalAlloc-before.TotalAlloc,
+			after.Mallocs-before.Mallocs)
+	}
+}
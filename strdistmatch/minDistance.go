@@ -0,0 +1,45 @@
+package main
+
+import "github.com/nickwells/strdist.mod/v2/strdist"
+
+// filterMinDistance returns sd with any entry whose distance is below
+// prog.minDistance, or above prog.maxDistance, removed, preserving order,
+// then reversed to put the furthest matches first if prog.worst is set.
+// It is applied after findLikeGroup and before any top-N cut, so the
+// distance bounds only ever remove results, never resurrect ones already
+// excluded by a finder's threshold, and --worst only changes which end of
+// the sorted slice the top-N cut keeps. --min-distance is used in
+// deduplication workflows to filter out exact-duplicate or trivially-close
+// matches, keeping only genuinely different near-matches. --max-distance
+// is a tighter display cutoff, distinct from a finder's own threshold,
+// which governs membership rather than what gets shown.
+func (prog *Prog) filterMinDistance(sd []strdist.StrDist) []strdist.StrDist {
+	if prog.minDistance > 0 || prog.maxDistance > 0 {
+		filtered := make([]strdist.StrDist, 0, len(sd))
+
+		for _, s := range sd {
+			if prog.minDistance > 0 && s.Dist < prog.minDistance {
+				continue
+			}
+
+			if prog.maxDistance > 0 && s.Dist > prog.maxDistance {
+				continue
+			}
+
+			filtered = append(filtered, s)
+		}
+
+		sd = filtered
+	}
+
+	if prog.worst {
+		reversed := make([]strdist.StrDist, len(sd))
+		for i, s := range sd {
+			reversed[len(sd)-1-i] = s
+		}
+
+		sd = reversed
+	}
+
+	return sd
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// strDistLess mirrors the tie-break ordering strdist.Finder.FindLike
+// applies to its results (see its unexported lessThanFunc): primarily by
+// distance, then by closeness in length to the target string, then
+// lexically. It has to be reimplemented here since findLikeMinStrLenSide
+// (and findLikeTopK) bypass FindLike itself.
+func strDistLess(a, b strdist.StrDist, targetLen int) bool {
+	if a.Dist != b.Dist {
+		return a.Dist < b.Dist
+	}
+
+	lenDiff1 := len(a.Str) - targetLen
+	lenDiff2 := len(b.Str) - targetLen
+	sqLenDiff1, sqLenDiff2 := lenDiff1*lenDiff1, lenDiff2*lenDiff2
+
+	if sqLenDiff1 != sqLenDiff2 {
+		return sqLenDiff1 < sqLenDiff2
+	}
+
+	return a.Str < b.Str
+}
+
+// minStrLenSortLess returns a sort.Slice-style less func over sd using
+// strDistLess.
+func minStrLenSortLess(sd []strdist.StrDist, targetLen int) func(i, j int) bool {
+	return func(i, j int) bool {
+		return strDistLess(sd[i], sd[j], targetLen)
+	}
+}
+
+// findLikeMinStrLenSide behaves like f.FindLike(s, pop...) except that f's
+// MinStrLength excludes only the side, or sides, named by appliesTo,
+// rather than both the target and every population word as FindLike
+// always does. It has to duplicate FindLike's preparation, filtering and
+// ordering logic, since which side MinStrLength constrains is not
+// something FinderConfig can express.
+func findLikeMinStrLenSide(
+	f *strdist.Finder, s string, pop []string, appliesTo string,
+) []strdist.StrDist {
+	if len(pop) == 0 {
+		return nil
+	}
+
+	minLen := f.FinderConfig.MinStrLength
+
+	s = prepStr(f.FinderConfig, s)
+	if appliesTo != minStrLenApplyPopulation && len(s) < minLen {
+		return nil
+	}
+
+	dists := make([]strdist.StrDist, 0, len(pop))
+
+	for _, pOrig := range pop {
+		p := prepStr(f.FinderConfig, pOrig)
+
+		if appliesTo != minStrLenApplyTarget && len(p) < minLen {
+			continue
+		}
+
+		d := f.Algo.Dist(s, p)
+		if d > f.FinderConfig.Threshold {
+			continue
+		}
+
+		dists = append(dists, strdist.StrDist{Str: pOrig, Dist: d})
+	}
+
+	sort.Slice(dists, minStrLenSortLess(dists, len(s)))
+
+	return dists
+}
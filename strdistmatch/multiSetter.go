@@ -0,0 +1,1364 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nickwells/english.mod/english"
+	"github.com/nickwells/param.mod/v6/param"
+	"github.com/nickwells/param.mod/v6/psetter"
+	"github.com/nickwells/strdist.mod/v2/strdist"
+	"golang.org/x/exp/maps"
+)
+
+// the indexes of the raw submatch groups within a single subValueRE match,
+// as returned by FindAllStringSubmatchIndex: the key, the double-quoted
+// body, the single-quoted body and the bare (unquoted) token - exactly one
+// of the last three alternatives matches for any given match
+const (
+	rawKeyIdx = iota + 1
+	rawDqValIdx
+	rawSqValIdx
+	rawBareValIdx
+)
+
+const maxAltNames = 3
+
+// nameFinder is used to suggest alternatives for a mistyped name or
+// sub-value key. Levenshtein behaves better than the package default of
+// cosine for the very short strings such names typically are.
+var nameFinder = strdist.DefaultFinders[strdist.CaseBlindAlgoNameLevenshtein]
+
+// presetSubvalKey is the reserved subval name recognised by GetNamedValue
+// to select a preset from Presets. It has no entry in EntryValSetterMap
+// since it seeds the whole EntryVal rather than a single field.
+const presetSubvalKey = "preset"
+
+// The regular expression fragments used to build the subValueRE
+const (
+	keyRE     = `[_a-zA-Z][_a-zA-Z0-9]*`
+	dqStrRE   = `(?:[^"\\]|\\.)*`
+	sqStrRE   = `(?:[^'\\]|\\.)*`
+	bareStrRE = `[^\s"']+`
+)
+
+// defaultSubvalSep is the key/value separator assumed when
+// MultiSetterBase.SubvalSep is left unset, both between a subval's key
+// and its value and between the top-level name and its subvals.
+const defaultSubvalSep = "="
+
+// buildSubValueRE compiles a subValueRE-shaped regular expression using
+// sep as the key/value separator instead of the hard-coded "=", so that
+// a value containing "=" of its own can be given unambiguously by
+// choosing a different separator (e.g. ":").
+func buildSubValueRE(sep string) *regexp.Regexp {
+	sepRE := `\s*` + regexp.QuoteMeta(sep) + `\s*`
+
+	return regexp.MustCompile(
+		`\s*` + // match & skip any space at the start
+			`(` + keyRE + `)` + // match & keep the key
+			`(?:` + sepRE + // the separator and value are both optional,
+			`(?:"(` + dqStrRE + `)"|'(` + sqStrRE + `)'|(` + bareStrRE + `)))?` +
+			`\s*`) // match and skip any space at the end
+}
+
+var (
+	evsKeyRE = regexp.MustCompile(keyRE)
+
+	// subValueRE matches a single subval entry using the
+	// defaultSubvalSep. The value may be wrapped in double or single
+	// quotes - independently for each entry - so that a value needing
+	// embedded double quotes can be written with single quotes and vice
+	// versa, without any shell-quoting gymnastics. Alternatively, for
+	// the common case of a short numeric or boolean value with nothing
+	// to quote, it may be given bare, with no quotes at all, in which
+	// case it runs up to the next whitespace. The double-quoted body is
+	// captured in the group at rawDqValIdx, the single-quoted body at
+	// rawSqValIdx and the bare token at rawBareValIdx; at most one of
+	// the three is populated for any given match, see findSubValues. The
+	// separator and value are together optional, so a key may also be
+	// given with no value at all; setWithSubval only accepts that for a
+	// subval whose Setter's ValueReq is not Mandatory.
+	//
+	// A MultiSetterBase with a non-default SubvalSep compiles its own
+	// copy via buildSubValueRE instead of using this one.
+	subValueRE = buildSubValueRE(defaultSubvalSep)
+)
+
+const multiSetterValueForm = `name=subval="..." subval="..." ...`
+
+// subValueMatch is one parsed subval entry. HasValue is false only for a
+// bare key given with no "=value" part at all (e.g. just "overflowNGrams"),
+// which is only accepted for a subval whose Setter can be called with no
+// value, such as a psetter.Bool.
+type subValueMatch struct {
+	whole    string
+	key      string
+	value    string
+	hasValue bool
+}
+
+// findSubValues finds every re match in val and merges each match's
+// double-quoted, single-quoted and bare alternatives into a single value,
+// returning one subValueMatch per match so the rest of the parsing code
+// need not care which alternative, if any, was used. re should be
+// subValueRE or a buildSubValueRE(sep) copy.
+func findSubValues(re *regexp.Regexp, val string) []subValueMatch {
+	idxMatches := re.FindAllStringSubmatchIndex(val, -1)
+
+	subValues := make([]subValueMatch, 0, len(idxMatches))
+
+	for _, m := range idxMatches {
+		sv := subValueMatch{
+			whole: val[m[0]:m[1]],
+			key:   val[m[2*rawKeyIdx]:m[2*rawKeyIdx+1]],
+		}
+
+		switch {
+		case m[2*rawDqValIdx] != -1:
+			sv.value = val[m[2*rawDqValIdx]:m[2*rawDqValIdx+1]]
+			sv.hasValue = true
+		case m[2*rawSqValIdx] != -1:
+			sv.value = val[m[2*rawSqValIdx]:m[2*rawSqValIdx+1]]
+			sv.hasValue = true
+		case m[2*rawBareValIdx] != -1:
+			sv.value = val[m[2*rawBareValIdx]:m[2*rawBareValIdx+1]]
+			sv.hasValue = true
+		}
+
+		subValues = append(subValues, sv)
+	}
+
+	return subValues
+}
+
+// MultiSetterActionFunc is the type of a function that can be supplied to be
+// run after the value has been successfully changed
+type MultiSetterActionFunc func(entryValName string, entryValValue string) error
+
+// EntryValSetter holds the configuration for the entry value setters
+type EntryValSetter struct {
+	// Setter is the setter that will be called.
+	Setter param.Setter
+	// PostActionFuncs is a, possibly empty, list of functions to be called
+	// after the Setter has successfully completed
+	PostActionFuncs []MultiSetterActionFunc
+	// MustBeSet will force an error to be generated if this Setter is not
+	// called for a MultiSetter value.
+	MustBeSet bool
+	// AllowRepeat suppresses the "value has been set twice" error for this
+	// subval, letting the parameter value repeat it any number of times.
+	// The Setter's SetWithVal is called once per occurrence, in order, so
+	// a slice-backed Setter (e.g. psetter.StrListAppender) can accumulate
+	// one entry per occurrence. MustBeSet is unaffected: it is satisfied
+	// as soon as the subval has been seen once.
+	AllowRepeat bool
+	// DefaultVal need not be set. If non-nil and this subval is not given,
+	// ParseNamedValue calls Setter.SetWithVal with *DefaultVal once every
+	// supplied subval has been processed, so validation and
+	// PostActionFuncs run just as they would for an explicitly given
+	// value. This differs from relying on the zero value already present
+	// via DfltEntryVal, which sets the field directly and so skips the
+	// Setter entirely. Applying the default also satisfies MustBeSet.
+	// It is not applied to a name given with no subvals at all, which
+	// takes DfltEntryVal as it is, nor when merging into an existing entry
+	// (see MapMultiSetter.MergeExisting), which keeps that entry's value.
+	DefaultVal *string
+}
+
+// NamedValue associates a name with a value. It is used as the entry type in
+// a slice of values when the MultiSetter is populating a slice rather than a
+// map. The MultiSetter associates a name (of type S) with a value (of type
+// T).
+//
+// If you want there to be only one value for each name then you can make
+// the association using a map.
+//
+// If you want to have multiple, different values for a given name then you
+// can make the association with a slice of NamedValue's.
+type NamedValue[S ~string, T any] struct {
+	Name  S
+	Value T
+
+	// SetKeys lists the canonical (post-alias-resolution) subval keys
+	// that were explicitly given in the parameter value, in the order
+	// they appeared. A subval populated only because its EntryValSetter
+	// had a DefaultVal, and not given explicitly, is not included, so
+	// this can be used to tell "the user gave this" apart from "this
+	// came from a default".
+	SetKeys []string
+}
+
+// MapMultiSetter allows multiple values to be set in a map entry with a
+// single parameter. The complexity in the setup is mostly in the setting of
+// the MultiSetter embedded type; see the documentation for that type for
+// details on how to initialise it.
+//
+// For the rest you must firstly, as usual, set the Value pointer to the
+// value you want to set. The value must be a map from your string type to
+// your data type
+//
+// Then populate the MultiSetter.
+type MapMultiSetter[S ~string, T any] struct {
+	psetter.ValueReqMandatory
+
+	// Value must be set, the program will panic if not. This is the map of
+	// values that this setter is setting
+	Value *map[S]T
+	// AllowHiddenMapEntries lets you have a Value which has an existing
+	// entry whose key is not in the MultiSetter's AVals map. Normally a
+	// Value having an illegal key would cause a panic from CheckSetter but
+	// this allows such entries. Note that this has no effect if the AVals
+	// map is empty. Note also that any entry with a disallowed key cannot be
+	// changed through this param.Setter.
+	AllowHiddenMapEntries bool
+
+	// DisallowDuplicateNames need not be set. Normally giving the same
+	// top-level name twice just overwrites the earlier entry with the
+	// later one. Setting this to true makes a repeated name an error
+	// instead, so a copy/paste mistake is reported rather than silently
+	// discarding the first value. It is mutually exclusive with
+	// MergeExisting in intent, though nothing stops both being set - with
+	// both set a repeated name is still an error, so MergeExisting never
+	// gets a chance to act.
+	DisallowDuplicateNames bool
+
+	// MergeExisting need not be set. Normally each occurrence of a given
+	// name starts from DfltEntryVal, so subvals not given on this
+	// occurrence revert to their default. Setting this to true instead
+	// seeds EntryVal from the existing map entry, if there is one, so
+	// that a later "--param name=..." only needs to give the subvals it
+	// wants to change and the rest of the entry carries over unchanged.
+	// A subval whose EntryValSetter has MustBeSet is only required to
+	// have been set on some occurrence, not on the one that finally
+	// leaves it unchanged - each occurrence is checked against the
+	// EntryVal it seeded, so a MustBeSet subval already present in the
+	// existing entry need not be repeated.
+	MergeExisting bool
+
+	// MultiSetter does the heavy lifting for this Setter type. It provides
+	// the bulk of the code that implements the Setter interface.
+	MultiSetterBase[S, T]
+}
+
+// SetWithVal populates the Value map with the parameters given by the
+// paramVal. If any error is reported the Value is left unchanged.
+func (s *MapMultiSetter[S, T]) SetWithVal(_, paramVal string) error {
+	name, _, ok := strings.Cut(paramVal, s.sep())
+
+	var (
+		nv  NamedValue[S, T]
+		err error
+	)
+
+	if existing, found := (*s.Value)[S(name)]; ok && found && s.MergeExisting {
+		nv, err = s.getNamedValueFrom(paramVal, existing)
+	} else {
+		nv, err = s.GetNamedValue("", paramVal)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if s.DisallowDuplicateNames {
+		if _, ok := (*s.Value)[nv.Name]; ok {
+			return fmt.Errorf("the value for %q has already been set", nv.Name)
+		}
+	}
+
+	(*s.Value)[nv.Name] = nv.Value
+
+	return nil
+}
+
+// sep returns the separator used for the top-level name=subvals cut:
+// SubvalSep if set, otherwise defaultSubvalSep.
+func (s *MapMultiSetter[S, T]) sep() string {
+	if s.SubvalSep != "" {
+		return s.SubvalSep
+	}
+
+	return defaultSubvalSep
+}
+
+// CurrentValue returns the current setting of the parameter value. The
+// entries are sorted by key so that the result is stable across calls
+// (map iteration order is not), which matters for anyone diffing
+// golden-file --help or config-dump output.
+func (s MapMultiSetter[S, T]) CurrentValue() string {
+	keys := make([]string, 0, len(*s.Value))
+	for k := range *s.Value {
+		keys = append(keys, string(k))
+	}
+
+	sort.Strings(keys)
+
+	valueParts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		valueParts = append(valueParts, fmt.Sprintf("%q: %#v", k, (*s.Value)[S(k)]))
+	}
+
+	return strings.Join(valueParts, " ")
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil, if there are no EntryValSetters
+func (s MapMultiSetter[S, T]) CheckSetter(name string) {
+	intro := name + ": MultiSetterMap Check failed: "
+
+	s.checkValue(intro)
+	s.CheckMultiSetter(intro)
+}
+
+// Reset clears the Value map back to empty and restores EntryVal to the
+// DfltEntryVal, ready to populate the map afresh. It does not touch any of
+// the setter configuration.
+func (s *MapMultiSetter[S, T]) Reset() {
+	*s.Value = map[S]T{}
+	s.MultiSetterBase.Reset()
+}
+
+// checkValue checks the Value and panics if it is invalid. Note that it also
+// sets the map to a non-nil value if the pointed to map is nil.
+func (s MapMultiSetter[S, T]) checkValue(intro string) {
+	if s.Value == nil {
+		panic(intro + "the Value to be set is nil")
+	}
+
+	if *s.Value == nil {
+		*s.Value = map[S]T{}
+	}
+
+	if len(s.AVals) == 0 ||
+		s.AllowHiddenMapEntries {
+		return
+	}
+
+	for k := range *s.Value {
+		if !s.AVals.ValueAllowed(string(k)) {
+			panic(fmt.Sprintf("%sthe map entry with key %q is invalid"+
+				" - it is not in the allowed values map",
+				intro, k))
+		}
+	}
+}
+
+// ListMultiSetter allows multiple values to be set in a list entry with a
+// single parameter. The complexity in the setup is mostly in the setting of
+// the MultiSetter embedded type; see the documentation for that type for
+// details on how to initialise it.
+//
+// For the rest you must firstly, as usual, set the Value pointer to the
+// value you want to set. The value must be a slice of NamedValue's of your
+// string type and your data type
+//
+// Then populate the MultiSetter.
+type ListMultiSetter[S ~string, T any] struct {
+	psetter.ValueReqMandatory
+
+	// Value must be set, the program will panic if not. This is the slice
+	// of values that this setter is appending to
+	Value *[]NamedValue[S, T]
+	// AllowInvalidListEntries lets you have a Value which has an existing
+	// entry whose key is not in the MultiSetter's AVals map. Normally a
+	// Value having an illegal key would cause a panic from CheckSetter but
+	// this allows such entries. Note that this has no effect if the AVals
+	// map is empty. Note also that any entry with a disallowed key cannot be
+	// changed through this param.Setter.
+	AllowInvalidListEntries bool
+
+	// MultiSetterBase does the heavy lifting for this Setter type. It provides
+	// the bulk of the code that implements the Setter interface.
+	MultiSetterBase[S, T]
+}
+
+// SetWithVal populates the Value map with the parameters given by the
+// paramVal. If any error is reported the Value is left unchanged.
+func (s *ListMultiSetter[S, T]) SetWithVal(_, paramVal string) error {
+	nv, err := s.GetNamedValue("", paramVal)
+	if err != nil {
+		return err
+	}
+
+	(*s.Value) = append((*s.Value), nv)
+
+	return nil
+}
+
+// CurrentValue returns the current setting of the parameter value
+func (s ListMultiSetter[S, T]) CurrentValue() string {
+	valueParts := []string{}
+	for _, nv := range *s.Value {
+		valueParts = append(valueParts,
+			fmt.Sprintf("%q: %#v", nv.Name, nv.Value))
+	}
+
+	return strings.Join(valueParts, " ")
+}
+
+// CheckSetter panics if the setter has not been properly created - if the
+// Value is nil, if there are no EntryValSetters
+func (s ListMultiSetter[S, T]) CheckSetter(name string) {
+	intro := name + ": MultiSetterList Check failed: "
+
+	s.checkValue(intro)
+	s.CheckMultiSetter(intro)
+}
+
+// Reset clears the Value slice back to empty and restores EntryVal to the
+// DfltEntryVal, ready to populate the slice afresh. It does not touch any
+// of the setter configuration.
+func (s *ListMultiSetter[S, T]) Reset() {
+	*s.Value = nil
+	s.MultiSetterBase.Reset()
+}
+
+// checkValue checks the Value and panics if it is invalid. A nil slice is
+// left as it is, since appending to it works.
+func (s ListMultiSetter[S, T]) checkValue(intro string) {
+	if s.Value == nil {
+		panic(intro + "the Value to be set is nil")
+	}
+
+	if len(s.AVals) == 0 ||
+		s.AllowInvalidListEntries {
+		return
+	}
+
+	for _, nv := range *s.Value {
+		if !s.AVals.ValueAllowed(string(nv.Name)) {
+			panic(fmt.Sprintf("%sthe map entry with key %q is invalid"+
+				" - it is not in the allowed values map",
+				intro, nv.Name))
+		}
+	}
+}
+
+// MultiSetterBase is the engine used by the ...MultiSetter types to
+// construct the named collection of values. It allows multiple values to be
+// set with a single parameter. It is a bit complicated to set up as it is
+// self-referential so there is a little more explanation than with most
+// param.Setters.
+//
+// Firstly, you can choose to set the DfltEntryVal to some value but if you
+// are happy with the zero values there is no need to do this. Whatever value
+// you give here will be copied into the EntryVal before setting the
+// EntryVal from the parameter value.
+//
+// Then you must construct the collection of EntryValSetterMap. Each
+// param.Setter here is called when a sub-string matches its name in the
+// EntryValSetterMap. The values that these param.Setters refer to should all
+// be members of the EntryVal. Also each param.Setter must be one that takes
+// a param value.
+//
+// The different ...MultiSetter types each have their own SetWithVal methods
+// which call the MultiSetterBase's GetNamedValue and use the results to
+// populate their own internal Value element. The GetNamedValue will copy the
+// DfltEntryVal over the EntryVal, call the EntryValSetters according to the
+// parameter value and then return a populated NamedValue which the
+// ...MultiSetter can use to populate its own Value member. If any errors are
+// detected then an empty NamedValue is returned.
+type MultiSetterBase[S ~string, T any] struct {
+	// DfltEntryVal holds the default values to give the entries in the Value
+	// map. If the zero values are OK there is no need to change this when
+	// creating the MultiSetter.
+	DfltEntryVal T
+	// EntryVal is used purely as a target for the EntryValSetters. Its value
+	// is overwritten each time the SetWithVal method is called when it is
+	// initialised to the DfltEntryVal
+	EntryVal T
+	// EntryValSetterMap must be set, the program will panic if not. Each
+	// param.Setter should have a Value that refers to a member of the
+	// MultiSetter.EntryVal. Also only setters that expect a value are
+	// allowed. The 'subval' names refer to entries in this map.
+	EntryValSetterMap map[string]EntryValSetter
+
+	// AVals need not be set but if it has any entries then they will be used
+	// to constrain the allowed 'name' part (note not the subval name part)
+	// of the value being set.
+	AVals psetter.AllowedVals[S]
+
+	// EntryValSMAliases need not be set but if it has any entries then the
+	// key must not appear in the EntryValSetterMap and the mapped value must
+	// appear.
+	EntryValSMAliases map[string]string
+
+	// AllowedSubvalNames need not be set but, if it has an entry for a
+	// given name, only the listed subval keys (their canonical
+	// EntryValSetterMap key, not an EntryValSMAliases alias) may be given
+	// when setting that name's value; any other subval produces a clear
+	// "does not apply to this name" error rather than being silently
+	// accepted. Names with no entry here have no such restriction.
+	AllowedSubvalNames map[S][]string
+
+	// Presets need not be set. If it has entries, a "preset" subval is
+	// recognised whose value must be a key in this map; the
+	// corresponding value is copied onto EntryVal, seeding it before any
+	// other subvals are applied, regardless of where "preset" appears in
+	// the parameter value. Other subvals given alongside it still
+	// override individual fields as normal, so a single subval can seed
+	// a common configuration that explicit subvals then fine-tune.
+	Presets map[string]T
+
+	// FinalCheck need not be set. If given, GetNamedValue calls it after
+	// every subval has been applied and the MustBeSet checks have
+	// passed, with EntryVal fully populated, letting it enforce rules
+	// that span more than one subval (for instance that one field must
+	// not exceed another). A non-nil error rejects the whole value.
+	FinalCheck func(name S, val T) error
+
+	// SubvalSep need not be set; it defaults to "=". It is the single
+	// character used to separate the top-level name from its subvals
+	// and each subval's key from its value, e.g. "name=subval="val"".
+	// Setting it, for instance to ":", lets a value that itself contains
+	// "=" be given without the ambiguity that would otherwise cause. It
+	// must be a single character that cannot appear in a key (so not a
+	// letter, digit or underscore) and is not whitespace, a quote or a
+	// backslash; CheckMultiSetter panics otherwise.
+	SubvalSep string
+
+	// CaseInsensitiveKeys need not be set. If true, a subval key is
+	// looked up in EntryValSetterMap and EntryValSMAliases regardless of
+	// case, so "ngramlen" and "NGRAMLEN" both resolve to "nGramLen".
+	// CheckMultiSetter panics at setup time if two of those keys would
+	// then be indistinguishable. The default is strict, exact matching.
+	CaseInsensitiveKeys bool
+}
+
+// Reset restores the EntryVal to the DfltEntryVal. It does not touch any
+// of the setter configuration (EntryValSetterMap, AVals, Presets, etc.) -
+// only the working state that GetNamedValue overwrites on every call.
+func (s *MultiSetterBase[S, T]) Reset() {
+	s.EntryVal = s.DfltEntryVal
+}
+
+// ParseSpec carries the configuration ParseNamedValue needs to parse a
+// "name=subval="..." subval="..." ..." parameter value: the sub-value
+// setters, their aliases, the allowed top-level names, and the presets
+// and cross-field check applied once parsing completes. It is the same
+// configuration a MultiSetterBase carries, minus the working state
+// (EntryVal is instead passed explicitly, since it must be the address
+// the EntryValSetterMap's Setters were built to point at), so the parser
+// can be exercised directly without going via a param.Setter or the
+// param package's lifecycle.
+type ParseSpec[S ~string, T any] struct {
+	// DfltEntryVal is copied onto EntryVal before parsing begins.
+	DfltEntryVal T
+	// EntryVal is the scratch value that the EntryValSetterMap's Setters
+	// populate; it must be the same address that they were constructed
+	// to point at.
+	EntryVal *T
+	// EntryValSetterMap must be set, the program will panic if not. Each
+	// param.Setter should have a Value that refers to a member of
+	// EntryVal. Also only setters that expect a value are allowed. The
+	// 'subval' names refer to entries in this map.
+	EntryValSetterMap map[string]EntryValSetter
+	// AVals need not be set but if it has any entries then they will be
+	// used to constrain the allowed 'name' part (note not the subval
+	// name part) of the value being set.
+	AVals psetter.AllowedVals[S]
+	// EntryValSMAliases need not be set but if it has any entries then
+	// the key must not appear in the EntryValSetterMap and the mapped
+	// value must appear.
+	EntryValSMAliases map[string]string
+	// AllowedSubvalNames need not be set but, if it has an entry for a
+	// given name, only the listed subval keys (their canonical
+	// EntryValSetterMap key, not an EntryValSMAliases alias) may be
+	// given when setting that name's value.
+	AllowedSubvalNames map[S][]string
+	// Presets need not be set. If it has entries, a "preset" subval is
+	// recognised whose value must be a key in this map; see
+	// MultiSetterBase.Presets for the full behaviour.
+	Presets map[string]T
+	// FinalCheck need not be set. If given, ParseNamedValue calls it
+	// after every subval has been applied, with EntryVal fully
+	// populated, and returns its error, if any, rejecting the whole
+	// value.
+	FinalCheck func(name S, val T) error
+	// Sep need not be set; it defaults to defaultSubvalSep ("="). It is
+	// the separator used both for the top-level name=subvals cut and,
+	// via SubValueRE, within each subval.
+	Sep string
+	// SubValueRE need not be set; it defaults to subValueRE (the
+	// defaultSubvalSep-based regular expression). Set it, via
+	// buildSubValueRE(sep), when Sep is not the default.
+	SubValueRE *regexp.Regexp
+	// CaseInsensitiveKeys need not be set; see
+	// MultiSetterBase.CaseInsensitiveKeys.
+	CaseInsensitiveKeys bool
+	// InitEntryVal need not be set; if nil, ParseNamedValue seeds EntryVal
+	// from DfltEntryVal as usual. If non-nil, EntryVal is seeded from
+	// *InitEntryVal instead, letting a caller build on top of an existing
+	// value (see MapMultiSetter.MergeExisting) rather than starting fresh.
+	InitEntryVal *T
+}
+
+// subValueRE returns the regular expression to use for finding subvalues:
+// spec.SubValueRE if set, otherwise the package default.
+func (spec ParseSpec[S, T]) subValueRE() *regexp.Regexp {
+	if spec.SubValueRE != nil {
+		return spec.SubValueRE
+	}
+
+	return subValueRE
+}
+
+// sep returns the separator to use for the top-level name=subvals cut:
+// spec.Sep if set, otherwise defaultSubvalSep.
+func (spec ParseSpec[S, T]) sep() string {
+	if spec.Sep != "" {
+		return spec.Sep
+	}
+
+	return defaultSubvalSep
+}
+
+// ParseNamedValue parses paramVal according to spec, independent of any
+// param.Setter or MultiSetterBase, and returns the resulting NamedValue.
+// GetNamedValue is a thin wrapper around this that builds a ParseSpec
+// from the MultiSetterBase's own fields.
+func ParseNamedValue[S ~string, T any](spec ParseSpec[S, T], paramVal string) (
+	NamedValue[S, T], error,
+) {
+	name, val, ok := strings.Cut(paramVal, spec.sep())
+
+	err := checkParamPartName(spec, name)
+	if err != nil {
+		return NamedValue[S, T]{}, err
+	}
+
+	if !ok {
+		return NamedValue[S, T]{Name: S(name), Value: spec.DfltEntryVal}, nil
+	}
+
+	if spec.InitEntryVal != nil {
+		*spec.EntryVal = *spec.InitEntryVal
+	} else {
+		*spec.EntryVal = spec.DfltEntryVal
+	}
+
+	subValues := findSubValues(spec.subValueRE(), val)
+	if len(subValues) == 0 {
+		return NamedValue[S, T]{},
+			fmt.Errorf("cannot get any values from the parameter: %q", val)
+	}
+
+	if err := applyPreset(spec, subValues); err != nil {
+		return NamedValue[S, T]{}, err
+	}
+
+	// baseOffset is the rune offset, within paramVal, of the start of val -
+	// i.e. past the "name" + separator already consumed by strings.Cut.
+	// It lets setWithSubval report each error's position relative to the
+	// whole paramVal rather than just its own, already-trimmed, val.
+	baseOffset := utf8.RuneCountInString(name) + utf8.RuneCountInString(spec.sep())
+	origVal := val
+
+	dups := map[string]string{}
+	setKeys := []string{}
+
+	for i, sVal := range subValues {
+		val, err = setWithSubval(spec, origVal, val, baseOffset, i, sVal, dups, &setKeys, S(name))
+		if err != nil {
+			return NamedValue[S, T]{}, err
+		}
+	}
+
+	// When merging into an existing entry (InitEntryVal set) its values
+	// carry over, so DefaultVal is not reapplied and MustBeSet, which the
+	// existing entry has already passed, is not checked again.
+	merging := spec.InitEntryVal != nil
+
+	for evKey, evs := range spec.EntryValSetterMap {
+		if evs.DefaultVal == nil || merging {
+			continue
+		}
+
+		if _, ok := dups[evKey]; ok {
+			continue
+		}
+
+		if err := evs.Setter.SetWithVal(evKey, *evs.DefaultVal); err != nil {
+			return NamedValue[S, T]{}, fmt.Errorf(
+				"bad default value for %q: %s", evKey, err)
+		}
+
+		for _, f := range evs.PostActionFuncs {
+			if err := f(evKey, *evs.DefaultVal); err != nil {
+				return NamedValue[S, T]{}, err
+			}
+		}
+
+		dups[evKey] = "<default>"
+	}
+
+	for evKey, evs := range spec.EntryValSetterMap {
+		if evs.MustBeSet && !merging {
+			if _, ok := dups[evKey]; !ok {
+				return NamedValue[S, T]{},
+					fmt.Errorf(
+						"the subvalue for %q must be set but hasn't been",
+						evKey)
+			}
+		}
+	}
+
+	// if any of the text is left after parsing, that is an error.
+	if val != "" {
+		pos := baseOffset + utf8.RuneCountInString(origVal) - utf8.RuneCountInString(val)
+
+		return NamedValue[S, T]{},
+			fmt.Errorf(
+				"unexpected text: %q, at the end of the parameter value"+
+					" (at position %d)",
+				val, pos)
+	}
+
+	if spec.FinalCheck != nil {
+		if err := spec.FinalCheck(S(name), *spec.EntryVal); err != nil {
+			return NamedValue[S, T]{}, err
+		}
+	}
+
+	// All's well, set the value.
+	return NamedValue[S, T]{Name: S(name), Value: *spec.EntryVal, SetKeys: setKeys}, nil
+}
+
+// GetNamedValue (called when a value follows the parameter) populates an entry
+// in Value map with the 'name' taken from the first part of the string
+// (before the '=', if any) and the 'subval' parts, if any, taken from the
+// parts after the '='. If the AllowedVals are not empty then the name must
+// be an allowed value.
+//
+// Note that, unusually, it takes a pointer receiver so a pointer to a
+// MultiSetter must be given to satisfy the param.Setter interface.
+func (s *MultiSetterBase[S, T]) GetNamedValue(_ string, paramVal string) (
+	NamedValue[S, T], error,
+) {
+	return s.getNamedValue(paramVal, nil)
+}
+
+// getNamedValueFrom is like GetNamedValue but seeds EntryVal from initVal
+// instead of DfltEntryVal, so subvals build on top of an existing value.
+// See MapMultiSetter.MergeExisting.
+func (s *MultiSetterBase[S, T]) getNamedValueFrom(paramVal string, initVal T) (
+	NamedValue[S, T], error,
+) {
+	return s.getNamedValue(paramVal, &initVal)
+}
+
+func (s *MultiSetterBase[S, T]) getNamedValue(paramVal string, initVal *T) (
+	NamedValue[S, T], error,
+) {
+	var re *regexp.Regexp
+	if s.SubvalSep != "" && s.SubvalSep != defaultSubvalSep {
+		re = buildSubValueRE(s.SubvalSep)
+	}
+
+	return ParseNamedValue(ParseSpec[S, T]{
+		DfltEntryVal:        s.DfltEntryVal,
+		EntryVal:            &s.EntryVal,
+		EntryValSetterMap:   s.EntryValSetterMap,
+		AVals:               s.AVals,
+		EntryValSMAliases:   s.EntryValSMAliases,
+		AllowedSubvalNames:  s.AllowedSubvalNames,
+		Presets:             s.Presets,
+		FinalCheck:          s.FinalCheck,
+		Sep:                 s.SubvalSep,
+		SubValueRE:          re,
+		CaseInsensitiveKeys: s.CaseInsensitiveKeys,
+		InitEntryVal:        initVal,
+	}, paramVal)
+}
+
+// applyPreset scans subValues for a "preset" subval and, if found, copies
+// the named entry from spec.Presets onto spec.EntryVal. It is called
+// before the main subval-processing loop so that a preset seeds
+// EntryVal's fields and any subval given alongside it - including an
+// explicit resetting of one of those same fields - is still free to
+// override them.
+func applyPreset[S ~string, T any](spec ParseSpec[S, T], subValues []subValueMatch) error {
+	if len(spec.Presets) == 0 {
+		return nil
+	}
+
+	for _, sVal := range subValues {
+		if sVal.key != presetSubvalKey {
+			continue
+		}
+
+		presetName := sVal.value
+
+		preset, ok := spec.Presets[presetName]
+		if !ok {
+			return fmt.Errorf("unknown preset: %q%s",
+				presetName,
+				SuggestAlternatives(
+					maxAltNames, presetName, maps.Keys(spec.Presets)))
+		}
+
+		*spec.EntryVal = preset
+
+		return nil
+	}
+
+	return nil
+}
+
+// checkParamPartName checks that the name part of the parameter value (the
+// part before the first '=') is valid and it will return an error if not.
+func checkParamPartName[S ~string, T any](spec ParseSpec[S, T], name string) error {
+	if name == "" {
+		return errors.New("the name may not be empty")
+	}
+
+	if len(spec.AVals) > 0 {
+		if !spec.AVals.ValueAllowed(name) {
+			pop := []string{}
+			for k := range spec.AVals {
+				pop = append(pop, string(k))
+			}
+
+			return fmt.Errorf(
+				"bad name: %q, the name is not recognised%s",
+				name, SuggestAlternativesWith(nameFinder, maxAltNames, name, pop))
+		}
+	}
+
+	return nil
+}
+
+// getSetter looks up evKey in spec's EntryValSetterMap, following
+// EntryValSMAliases if it isn't a direct match, and returns its canonical
+// key alongside the EntryValSetter. If any error is detected it is
+// returned.
+func getSetter[S ~string, T any](spec ParseSpec[S, T], i int, evKey string,
+) (string, EntryValSetter, error) {
+	if evs, ok := spec.EntryValSetterMap[evKey]; ok {
+		return evKey, evs, nil
+	}
+
+	if aliasKey, ok := spec.EntryValSMAliases[evKey]; ok {
+		if evs, ok := spec.EntryValSetterMap[aliasKey]; ok {
+			return aliasKey, evs, nil
+		}
+	}
+
+	if spec.CaseInsensitiveKeys {
+		if canonKey, evs, ok := getSetterCaseInsensitive(spec, evKey); ok {
+			return canonKey, evs, nil
+		}
+	}
+
+	entryValNames := maps.Keys(spec.EntryValSetterMap)
+	aliasNames := maps.Keys(spec.EntryValSMAliases)
+	entryValNames = append(entryValNames, aliasNames...)
+
+	return evKey, EntryValSetter{},
+		fmt.Errorf("bad sub-value name (%q), at the %d%s entry%s",
+			evKey, i+1, english.OrdinalSuffix(i+1),
+			SuggestAlternativesWith(nameFinder, maxAltNames, evKey, entryValNames))
+}
+
+// getSetterCaseInsensitive looks for evKey in spec.EntryValSetterMap and
+// spec.EntryValSMAliases ignoring case, returning the canonical key it
+// found. It is only called once the case-sensitive lookups in getSetter
+// have failed.
+func getSetterCaseInsensitive[S ~string, T any](
+	spec ParseSpec[S, T], evKey string,
+) (string, EntryValSetter, bool) {
+	lowerKey := strings.ToLower(evKey)
+
+	for k, evs := range spec.EntryValSetterMap {
+		if strings.ToLower(k) == lowerKey {
+			return k, evs, true
+		}
+	}
+
+	for alias, canonKey := range spec.EntryValSMAliases {
+		if strings.ToLower(alias) != lowerKey {
+			continue
+		}
+
+		if evs, ok := spec.EntryValSetterMap[canonKey]; ok {
+			return canonKey, evs, true
+		}
+	}
+
+	return "", EntryValSetter{}, false
+}
+
+// checkSubvalAllowed returns an error if spec.AllowedSubvalNames has an
+// entry for name and evKey (the canonical subval key) is not in it. If
+// AllowedSubvalNames has no entry for name then every subval is allowed.
+func checkSubvalAllowed[S ~string, T any](
+	spec ParseSpec[S, T], name S, evKey string,
+) error {
+	allowed, ok := spec.AllowedSubvalNames[name]
+	if !ok {
+		return nil
+	}
+
+	if slices.Contains(allowed, evKey) {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"the sub-value %q does not apply to %q%s",
+		evKey, name, SuggestAlternatives(maxAltNames, evKey, allowed))
+}
+
+// unescapeSubval un-escapes a subvalue string's body: a backslash removes
+// the special meaning of the character following it (so `\"` yields a
+// literal `"` and `\\` yields a literal `\`), letting a quoted subvalue
+// contain a quote character of its own, whichever quote style delimits
+// it. A trailing lone backslash, with nothing left to escape, is an
+// error.
+func unescapeSubval(s string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+
+		i++
+		if i >= len(s) {
+			return "", errors.New(
+				"a trailing backslash has nothing left to escape")
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String(), nil
+}
+
+// setWithSubval sets a field in the EntryVal by calling the appropriate
+// param.Setter. If any error is detected it is returned.
+func setWithSubval[S ~string, T any](
+	spec ParseSpec[S, T],
+	origVal string,
+	wholeValue string,
+	baseOffset int,
+	i int,
+	sVal subValueMatch,
+	dups map[string]string,
+	setKeys *[]string,
+	name S,
+) (string, error) {
+	const expectedVal = `expecting name="string" or a bare boolean name`
+
+	// pos is the rune offset, within the original paramVal, of the start
+	// of this entry - it lets a caller render a caret under the problem.
+	pos := baseOffset + utf8.RuneCountInString(origVal) - utf8.RuneCountInString(wholeValue)
+
+	// Now check that there is no text in the value before matched
+	// subval="..." part. This catches "syntax" errors in the value string.
+	wholeSubValue := sVal.whole
+
+	if !strings.HasPrefix(wholeValue, wholeSubValue) {
+		badVal, _, _ := strings.Cut(wholeValue, wholeSubValue)
+
+		return wholeValue,
+			fmt.Errorf(
+				"unexpected text: %q, before the %d%s entry: %q, %s"+
+					" (at position %d)",
+				badVal,
+				i+1, english.OrdinalSuffix(i+1),
+				wholeSubValue, expectedVal, pos)
+	}
+
+	wholeValue = strings.TrimPrefix(wholeValue, wholeSubValue)
+
+	// Now get the param.Setter for this subval
+	evKey, evVal, hasVal := sVal.key, sVal.value, sVal.hasValue
+
+	evVal, err := unescapeSubval(evVal)
+	if err != nil {
+		return wholeValue, fmt.Errorf(
+			"bad value for %q, at the %d%s entry: %s (at position %d)",
+			evKey, i+1, english.OrdinalSuffix(i+1), err, pos)
+	}
+
+	if evKey == presetSubvalKey {
+		if !hasVal {
+			return wholeValue, fmt.Errorf(
+				"%q must be given a value naming the preset,"+
+					" at the %d%s entry (at position %d)",
+				evKey, i+1, english.OrdinalSuffix(i+1), pos)
+		}
+
+		prevVal, dupFound := dups[evKey]
+		if dupFound {
+			return wholeValue, fmt.Errorf(
+				"the value for %q has been set twice,"+
+					" with %q and then with %q (the %d%s entry) (at position %d)",
+				evKey, prevVal, wholeSubValue,
+				i+1, english.OrdinalSuffix(i+1), pos)
+		}
+
+		dups[evKey] = wholeSubValue
+		*setKeys = append(*setKeys, evKey)
+
+		return wholeValue, nil
+	}
+
+	evKey, evs, err := getSetter(spec, i, evKey)
+	if err != nil {
+		return wholeValue, fmt.Errorf("%w (at position %d)", err, pos)
+	}
+
+	if err := checkSubvalAllowed(spec, name, evKey); err != nil {
+		return wholeValue, fmt.Errorf("%w (at position %d)", err, pos)
+	}
+
+	if !hasVal && evs.Setter.ValueReq() == param.Mandatory {
+		return wholeValue, fmt.Errorf(
+			"the value for %q must be given, at the %d%s entry (at position %d)",
+			evKey, i+1, english.OrdinalSuffix(i+1), pos)
+	}
+
+	// Now check that we haven't seen this subval key before, unless it's
+	// allowed to repeat
+	prevVal, dupFound := dups[evKey]
+	if dupFound && !evs.AllowRepeat {
+		return wholeValue, fmt.Errorf(
+			"the value for %q has been set twice,"+
+				" with %q and then with %q (the %d%s entry) (at position %d)",
+			evKey, prevVal, wholeSubValue,
+			i+1, english.OrdinalSuffix(i+1), pos)
+	}
+
+	dups[evKey] = wholeSubValue
+	*setKeys = append(*setKeys, evKey)
+
+	// Lastly run the param.Setter's Set or SetWithVal method
+	if !hasVal {
+		if err := evs.Setter.Set(evKey); err != nil {
+			return wholeValue, err
+		}
+	} else if err := evs.Setter.SetWithVal(evKey, evVal); err != nil {
+		return wholeValue, err
+	}
+
+	for _, f := range evs.PostActionFuncs {
+		if err := f(evKey, evVal); err != nil {
+			return wholeValue, err
+		}
+	}
+
+	return wholeValue, nil
+}
+
+// allowedValuesNames returns a string reflecting the allowed name
+// values. Note that this may be empty if the AVals map is empty.
+func (s MultiSetterBase[S, T]) allowedValuesNames() string {
+	if len(s.AVals) == 0 {
+		return ""
+	}
+
+	str := "\n\n" +
+		"the allowed names are"
+	names, maxLen := s.AVals.Keys()
+
+	sort.Strings(names)
+
+	for _, n := range names {
+		str += fmt.Sprintf("\n- %-*s: %s",
+			maxLen, n, s.AVals[S(n)])
+	}
+
+	return str
+}
+
+// allowedValuesSubvals returns a string reflecting the subval names and
+// allowed values
+func (s MultiSetterBase[S, T]) allowedValuesSubvals() string {
+	str := "\n\n" +
+		"the allowed"
+	if len(s.EntryValSetterMap) > 1 {
+		str += " subval names and values are:"
+	} else {
+		str += " subval name and value is:"
+	}
+
+	maxLen := 0
+	evsKeys := []string{}
+
+	for k := range s.EntryValSetterMap {
+		evsKeys = append(evsKeys, k)
+
+		if len(k) > maxLen {
+			maxLen = len(k)
+		}
+	}
+
+	sort.Strings(evsKeys)
+
+	valSeenBefore := map[string]string{}
+
+	for _, k := range evsKeys {
+		val := s.EntryValSetterMap[k].Setter.AllowedValues()
+		if prevKey, ok := valSeenBefore[val]; ok {
+			val = `as for "` + prevKey + `"`
+		} else {
+			valSeenBefore[val] = k
+		}
+
+		str += fmt.Sprintf("\n- %-*s: %s", maxLen, k, val)
+	}
+
+	return str
+}
+
+// allowedValuesSubvalAliases returns a string given any alias names
+// allowed. Note that this can be empty if the Aliases map is empty.
+func (s MultiSetterBase[S, T]) allowedValuesSubvalAliases() string {
+	if len(s.EntryValSMAliases) == 0 {
+		return ""
+	}
+
+	str := "\n\n"
+
+	if len(s.EntryValSMAliases) == 1 {
+		str += "the following alias for the subval name is allowed: "
+	} else {
+		str += "the following aliases for the subval names are allowed: "
+	}
+
+	maxLen := 0
+	aliases := []string{}
+
+	for k := range s.EntryValSMAliases {
+		aliases = append(aliases, k)
+
+		if len(k) > maxLen {
+			maxLen = len(k)
+		}
+	}
+
+	sort.Strings(aliases)
+
+	for _, k := range aliases {
+		str += fmt.Sprintf("\n- %-*s: %s", maxLen, k, s.EntryValSMAliases[k])
+	}
+
+	return str
+}
+
+// allowedValuesPresets returns a string listing the preset names, if any
+// Presets have been given, or the empty string otherwise
+func (s MultiSetterBase[S, T]) allowedValuesPresets() string {
+	if len(s.Presets) == 0 {
+		return ""
+	}
+
+	names := maps.Keys(s.Presets)
+	sort.Strings(names)
+
+	str := "\n\nthe " + presetSubvalKey +
+		" subval seeds all the other subvals from a named preset" +
+		" before they are applied; the allowed presets are: " +
+		strings.Join(names, ", ")
+
+	return str
+}
+
+// AllowedValues returns a string describing the allowed values
+func (s MultiSetterBase[S, T]) AllowedValues() string {
+	avStr := "a value of the form " + multiSetterValueForm
+
+	avStr += s.allowedValuesNames()
+
+	avStr += s.allowedValuesSubvals()
+
+	avStr += s.allowedValuesSubvalAliases()
+
+	avStr += s.allowedValuesPresets()
+
+	return avStr
+}
+
+// ValDescribe returns a short string illustrating the value to be supplied
+func (s MultiSetterBase[S, T]) ValDescribe() string {
+	return multiSetterValueForm
+}
+
+// ValueGrammar returns a description of the exact syntax accepted for the
+// parameter value: the regular expression used to split the sub-values
+// apart plus the human-readable AllowedValues description. It is intended
+// for documentation and debugging so that a caller building a complex
+// --algo-style value can see precisely what will be matched.
+func (s MultiSetterBase[S, T]) ValueGrammar() string {
+	re := subValueRE
+	if s.SubvalSep != "" && s.SubvalSep != defaultSubvalSep {
+		re = buildSubValueRE(s.SubvalSep)
+	}
+
+	str := "value form: " + multiSetterValueForm +
+		"\n\nname part: " + keyRE +
+		"\n\nsub-value part (repeated, space-separated): " +
+		re.String()
+
+	str += s.allowedValuesNames()
+	str += s.allowedValuesSubvals()
+	str += s.allowedValuesSubvalAliases()
+	str += s.allowedValuesPresets()
+
+	return str
+}
+
+// CheckMultiSetter panics if the multi-setter has not been properly created
+func (s MultiSetterBase[S, T]) CheckMultiSetter(intro string) {
+	s.checkAllowedValues(intro)
+	s.checkEntryValSetters(intro)
+	s.checkEntryValSetterMapAliases(intro)
+	s.checkAllowedSubvalNames(intro)
+	s.checkSubvalSep(intro)
+	s.checkCaseInsensitiveKeys(intro)
+}
+
+// checkCaseInsensitiveKeys checks that, if CaseInsensitiveKeys is set, no
+// two keys across EntryValSetterMap and EntryValSMAliases differ only by
+// case, and panics if they do - such keys would be indistinguishable
+// once lower-cased.
+func (s MultiSetterBase[S, T]) checkCaseInsensitiveKeys(intro string) {
+	if !s.CaseInsensitiveKeys {
+		return
+	}
+
+	seen := map[string]string{}
+
+	allKeys := make([]string, 0, len(s.EntryValSetterMap)+len(s.EntryValSMAliases))
+	allKeys = append(allKeys, maps.Keys(s.EntryValSetterMap)...)
+	allKeys = append(allKeys, maps.Keys(s.EntryValSMAliases)...)
+
+	for _, k := range allKeys {
+		lower := strings.ToLower(k)
+
+		if prev, ok := seen[lower]; ok {
+			panic(fmt.Sprintf(
+				"%sCaseInsensitiveKeys is set but %q and %q"+
+					" differ only by case",
+				intro, prev, k))
+		}
+
+		seen[lower] = k
+	}
+}
+
+// checkSubvalSep checks the SubvalSep and panics if it is invalid. It must
+// be a single character that cannot appear in a key (a letter, digit or
+// underscore) and is not whitespace, a quote or a backslash, all of which
+// have their own meaning in the subValueRE grammar.
+func (s MultiSetterBase[S, T]) checkSubvalSep(intro string) {
+	if s.SubvalSep == "" {
+		return
+	}
+
+	if len(s.SubvalSep) != 1 {
+		panic(intro + "SubvalSep must be a single character")
+	}
+
+	c := rune(s.SubvalSep[0])
+
+	switch {
+	case unicode.IsSpace(c):
+		panic(intro + "SubvalSep must not be whitespace")
+	case c == '"' || c == '\'' || c == '\\':
+		panic(intro + "SubvalSep must not be a quote character or a backslash")
+	case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_':
+		panic(intro +
+			"SubvalSep must not be a letter, digit or underscore" +
+			" - it would be ambiguous with a key character")
+	}
+}
+
+// checkAllowedValues checks the AVals and panics if there are any
+// problems. It also checks that any existing entries in the Value map have
+// keys in the AVals map.
+func (s MultiSetterBase[S, T]) checkAllowedValues(intro string) {
+	if len(s.AVals) == 0 {
+		return
+	}
+
+	if err := s.AVals.Check(); err != nil {
+		panic(intro + err.Error())
+	}
+}
+
+// checkEntryValSetters checks the EntryValSetters and panics if it is
+// invalid. It checks that the keys (the names of the sub-entries) match the
+// regular expression, that the setters all take a value and that the
+// individual setters themselves pass their own checks.
+func (s MultiSetterBase[S, T]) checkEntryValSetters(intro string) {
+	if len(s.EntryValSetterMap) == 0 {
+		panic(intro + "there must be at least one sub-value setter")
+	}
+
+	for k, evs := range s.EntryValSetterMap {
+		evsIntro := fmt.Sprintf("%sbad entry-value setter: %q: ", intro, k)
+
+		if !evsKeyRE.MatchString(k) {
+			panic(fmt.Sprintf(
+				"%sbad key %q: it should be"+
+					" a letter followed by zero or more letters or numbers",
+				evsIntro, k))
+		}
+
+		if evs.Setter.ValueReq() == param.None {
+			panic(fmt.Sprintf("%sit must take a value", evsIntro))
+		}
+
+		evs.Setter.CheckSetter(intro + ".SubTypeSetters[" + k + "]")
+	}
+}
+
+// checkEntryValSetterMapAliases checks the EntryValSMAliases and panics if
+// there are any problems.
+func (s MultiSetterBase[S, T]) checkEntryValSetterMapAliases(intro string) {
+	for k, v := range s.EntryValSMAliases {
+		if _, ok := s.EntryValSetterMap[k]; ok {
+			panic(fmt.Sprintf(
+				"%sthe alias %q is the same as a subval name", intro, k))
+		}
+
+		if _, ok := s.EntryValSetterMap[v]; !ok {
+			panic(fmt.Sprintf(
+				"%sthe alias %q (= %q) does not refer to a subval name",
+				intro, k, v))
+		}
+	}
+}
+
+// checkAllowedSubvalNames checks the AllowedSubvalNames and panics if it
+// refers to a name not in AVals or a subval key not in the
+// EntryValSetterMap.
+func (s MultiSetterBase[S, T]) checkAllowedSubvalNames(intro string) {
+	for name, allowed := range s.AllowedSubvalNames {
+		if len(s.AVals) > 0 && !s.AVals.ValueAllowed(string(name)) {
+			panic(fmt.Sprintf(
+				"%sthe AllowedSubvalNames entry %q is not a known name",
+				intro, name))
+		}
+
+		for _, evKey := range allowed {
+			if _, ok := s.EntryValSetterMap[evKey]; !ok {
+				panic(fmt.Sprintf(
+					"%sthe AllowedSubvalNames entry for %q allows"+
+						" the unknown sub-value %q",
+					intro, name, evKey))
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/nickwells/param.mod/v6/psetter"
+	"github.com/nickwells/testhelper.mod/v2/testhelper"
+)
+
+func TestUnescapeSubval(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		in     string
+		expVal string
+	}{
+		{
+			ID:     testhelper.MkID("no escapes"),
+			in:     `ab`,
+			expVal: `ab`,
+		},
+		{
+			ID:     testhelper.MkID("escaped quote"),
+			in:     `a\"b`,
+			expVal: `a"b`,
+		},
+		{
+			ID:     testhelper.MkID("escaped backslash"),
+			in:     `a\\b`,
+			expVal: `a\b`,
+		},
+		{
+			ID:     testhelper.MkID("trailing lone backslash"),
+			in:     `ab\`,
+			ExpErr: testhelper.MkExpErr("nothing left to escape"),
+			expVal: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		val, err := unescapeSubval(tc.in)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			testhelper.DiffString(t, tc.IDStr(), "value", val, tc.expVal)
+		}
+	}
+}
+
+// widget is a minimal EntryVal type for exercising ParseNamedValue and
+// MultiSetterBase.Reset directly, independent of algoParams and its
+// --algo-shaped subvals.
+type widget struct {
+	size   int
+	shiny  bool
+	colour string
+}
+
+// widgetParseSpec builds a ParseSpec whose EntryValSetterMap sets ev's
+// fields, for use directly with ParseNamedValue.
+func widgetParseSpec(ev *widget) ParseSpec[string, widget] {
+	return ParseSpec[string, widget]{
+		DfltEntryVal: widget{size: 1},
+		EntryVal:     ev,
+		EntryValSetterMap: map[string]EntryValSetter{
+			"size": {
+				Setter: psetter.Int[int]{Value: &ev.size},
+			},
+			"shiny": {
+				Setter: psetter.Bool{Value: &ev.shiny},
+			},
+			"colour": {
+				Setter:    psetter.String[string]{Value: &ev.colour},
+				MustBeSet: true,
+			},
+		},
+	}
+}
+
+func TestParseNamedValue(t *testing.T) {
+	testCases := []struct {
+		testhelper.ID
+		testhelper.ExpErr
+		paramVal string
+		expNV    NamedValue[string, widget]
+	}{
+		{
+			ID:       testhelper.MkID("name only, no subvals"),
+			paramVal: "widget1",
+			expNV: NamedValue[string, widget]{
+				Name: "widget1", Value: widget{size: 1},
+			},
+		},
+		{
+			ID:       testhelper.MkID("subvals set every field"),
+			paramVal: `widget1=size=2 shiny=true colour="red"`,
+			expNV: NamedValue[string, widget]{
+				Name:  "widget1",
+				Value: widget{size: 2, shiny: true, colour: "red"},
+			},
+		},
+		{
+			ID:       testhelper.MkID("unset field keeps its default"),
+			paramVal: `widget1=colour=green`,
+			expNV: NamedValue[string, widget]{
+				Name:  "widget1",
+				Value: widget{size: 1, colour: "green"},
+			},
+		},
+		{
+			ID: testhelper.MkID(
+				"escaped quote inside a quoted subval doesn't end it early"),
+			paramVal: `widget1=colour="a\"b" size=2`,
+			expNV: NamedValue[string, widget]{
+				Name:  "widget1",
+				Value: widget{size: 2, colour: `a"b`},
+			},
+		},
+		{
+			ID:       testhelper.MkID("MustBeSet subval missing"),
+			paramVal: `widget1=size=2`,
+			ExpErr:   testhelper.MkExpErr("colour", "must be set"),
+		},
+		{
+			ID:       testhelper.MkID("unknown subval key"),
+			paramVal: `widget1=weight=2`,
+			ExpErr:   testhelper.MkExpErr("weight"),
+		},
+	}
+
+	for _, tc := range testCases {
+		var ev widget
+
+		nv, err := ParseNamedValue(widgetParseSpec(&ev), tc.paramVal)
+		if testhelper.CheckExpErr(t, err, tc) && err == nil {
+			if nv.Name != tc.expNV.Name || nv.Value != tc.expNV.Value {
+				t.Errorf("%s: ParseNamedValue(%q) == {%q %#v}, want {%q %#v}",
+					tc.IDStr(), tc.paramVal,
+					nv.Name, nv.Value, tc.expNV.Name, tc.expNV.Value)
+			}
+		}
+	}
+}
+
+// TestMultiSetterBaseReset checks that, after Reset, GetNamedValue starts
+// each subsequent parse from DfltEntryVal again rather than carrying over
+// EntryVal from the previous call - the behaviour that lets a single
+// MultiSetterBase be reused across several parse passes, as ParseNamedValue
+// tests above already need to instead work around by taking a fresh EntryVal
+// each time.
+func TestMultiSetterBaseReset(t *testing.T) {
+	var msb MultiSetterBase[string, widget]
+
+	msb.DfltEntryVal = widget{size: 1}
+	msb.EntryValSetterMap = map[string]EntryValSetter{
+		"size": {
+			Setter: psetter.Int[int]{Value: &msb.EntryVal.size},
+		},
+	}
+
+	nv, err := msb.GetNamedValue("", "widget1=size=5")
+	if err != nil {
+		t.Fatalf("first GetNamedValue: %s", err)
+	}
+
+	if nv.Value.size != 5 {
+		t.Fatalf("first GetNamedValue: size == %d, want 5", nv.Value.size)
+	}
+
+	msb.Reset()
+
+	nv, err = msb.GetNamedValue("", "widget2")
+	if err != nil {
+		t.Fatalf("second GetNamedValue (after Reset): %s", err)
+	}
+
+	if nv.Value.size != 1 {
+		t.Errorf("second GetNamedValue (after Reset): size == %d, want 1"+
+			" (Reset should restore DfltEntryVal, not carry over the"+
+			" previous EntryVal)", nv.Value.size)
+	}
+}
+
+// TestListMultiSetterReset checks that Reset lets a single ListMultiSetter
+// be reused to build up a second, independent slice of values rather than
+// continuing to append to the first.
+func TestListMultiSetterReset(t *testing.T) {
+	var (
+		value []NamedValue[string, widget]
+		lms   ListMultiSetter[string, widget]
+	)
+
+	lms.Value = &value
+	lms.EntryValSetterMap = map[string]EntryValSetter{
+		"size": {
+			Setter: psetter.Int[int]{Value: &lms.EntryVal.size},
+		},
+	}
+
+	if err := lms.SetWithVal("", "widget1=size=2"); err != nil {
+		t.Fatalf("first SetWithVal: %s", err)
+	}
+
+	if len(value) != 1 {
+		t.Fatalf("after first SetWithVal: len(value) == %d, want 1", len(value))
+	}
+
+	lms.Reset()
+
+	if err := lms.SetWithVal("", "widget2=size=3"); err != nil {
+		t.Fatalf("second SetWithVal (after Reset): %s", err)
+	}
+
+	if len(value) != 1 {
+		t.Fatalf("after Reset and second SetWithVal: len(value) == %d, want 1"+
+			" (Reset should clear the slice, not append to the old one)",
+			len(value))
+	}
+
+	if value[0].Name != "widget2" || value[0].Value.size != 3 {
+		t.Errorf("after Reset: value[0] == %#v, want {widget2 {3 false }}",
+			value[0])
+	}
+}
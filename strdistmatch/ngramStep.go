@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// dfltNGramStep is the step between the start of successive n-grams; a step
+// of 1 gives the standard sliding-window behaviour.
+const dfltNGramStep = 1
+
+// steppedNGrams builds an NGramSet from s in the same way as
+// strdist.NGramConfig.NGrams, except that successive n-grams start step
+// runes apart rather than one. strdist.mod has no such knob so this is
+// implemented directly against its exported NGramSet type rather than by
+// going through NGramConfig.
+func steppedNGrams(s string, length, step int) strdist.NGramSet {
+	ngs := strdist.NGramSet{}
+	runes := []rune(s)
+
+	for i := 0; i+length <= len(runes); i += step {
+		ngs[string(runes[i:i+length])]++
+	}
+
+	return ngs
+}
+
+// wordShingles builds an NGramSet from s in the same way as steppedNGrams,
+// except that the n-grams (shingles) are runs of whitespace-separated
+// words rather than runs of runes. This suits document/phrase similarity,
+// where character n-grams are too fine-grained to be meaningful.
+func wordShingles(s string, length, step int) strdist.NGramSet {
+	ngs := strdist.NGramSet{}
+	words := strings.Fields(s)
+
+	for i := 0; i+length <= len(words); i += step {
+		ngs[strings.Join(words[i:i+length], " ")]++
+	}
+
+	return ngs
+}
+
+// steppedNGramAlgo is a strdist.Algo which compares strings using n-grams
+// built with a configurable step, delegating the similarity calculation
+// itself to the same functions strdist.mod uses for its own n-gram algos.
+// With wordLevel set the n-grams are word shingles built by wordShingles
+// rather than the usual runs of runes.
+type steppedNGramAlgo struct {
+	name      string
+	length    int
+	step      int
+	wordLevel bool
+	index     func(ngs1, ngs2 strdist.NGramSet) float64
+}
+
+// Dist returns 1 minus the configured similarity index of the stepped
+// n-gram sets of s1 and s2.
+func (a steppedNGramAlgo) Dist(s1, s2 string) float64 {
+	ngramsOf := steppedNGrams
+	if a.wordLevel {
+		ngramsOf = wordShingles
+	}
+
+	ngs1 := ngramsOf(s1, a.length, a.step)
+	ngs2 := ngramsOf(s2, a.length, a.step)
+
+	return 1.0 - a.index(ngs1, ngs2)
+}
+
+// Name returns the algorithm name
+func (a steppedNGramAlgo) Name() string {
+	return a.name
+}
+
+// Desc returns a string describing the algorithm configuration
+func (a steppedNGramAlgo) Desc() string {
+	if a.wordLevel {
+		return fmt.Sprintf("Word Shingles: Len: %2d Step: %2d",
+			a.length, a.step)
+	}
+
+	return fmt.Sprintf("N-Gram: Len: %2d Step: %2d", a.length, a.step)
+}
+
+// checkNGramStep returns a non-nil error if step is not a valid step for
+// n-grams of the given length.
+func checkNGramStep(step, length int) error {
+	if step < 1 {
+		return fmt.Errorf("the n-gram step (%d) must be >= 1", step)
+	}
+
+	if step > length {
+		return fmt.Errorf(
+			"the n-gram step (%d) must be <= the n-gram length (%d)",
+			step, length)
+	}
+
+	return nil
+}
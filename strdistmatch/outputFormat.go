@@ -0,0 +1,12 @@
+package main
+
+// outputFormat names the shape --output-format writes the report in, in
+// place of the default column-formatted table.
+type outputFormat string
+
+const (
+	outputFormatTable    outputFormat = "table"
+	outputFormatJSON     outputFormat = "json"
+	outputFormatCSV      outputFormat = "csv"
+	outputFormatMarkdown outputFormat = "markdown"
+)
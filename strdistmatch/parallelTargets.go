@@ -0,0 +1,227 @@
+package main
+
+import (
+	"sync"
+	"unicode/utf8"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// targetResult holds one target's computed report rows together with the
+// stats and audit updates its computation produced. Keeping these local
+// to the target, rather than writing straight into prog's shared state,
+// is what makes computeTargetResult safe to run concurrently for several
+// targets: the caller applies the update once it drains the result, in
+// the original target order.
+type targetResult struct {
+	target           string
+	rows             [][]any
+	matched          bool
+	algoMatches      map[string]int
+	algoResultCounts map[string]int
+	auditHits        []auditRecord
+}
+
+// computeTargetResult computes the report rows for a single target,
+// exactly as the body of Run's main loop used to, but returning its
+// stats and audit updates instead of applying them directly, so that it
+// can be called for several targets at once from computeTargetResults.
+func (prog *Prog) computeTargetResult(
+	s string, finders []*strdist.Finder, searchPop []string,
+	origOf map[string]string, find findLikeFunc, maxConcurrentAlgos int,
+) targetResult {
+	group := parseTargetGroup(s)
+	if prog.transliterate {
+		group = transliterateGroup(group)
+	}
+
+	if prog.tokenise {
+		group = tokeniseGroup(prog.tokenRegex, group)
+	}
+
+	if prog.graphemeClusters {
+		group = prog.graphemeEnc.encodeGroup(group)
+	}
+
+	sdByFinder := make([][]strdist.StrDist, len(finders))
+	sem := make(chan struct{}, maxConcurrentAlgos)
+
+	var wg sync.WaitGroup
+
+	for i, f := range finders {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, f *strdist.Finder) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			sdByFinder[i] = prog.filterMinDistance(
+				findLikeGroup(f, group, searchPop, find))
+		}(i, f)
+	}
+
+	wg.Wait()
+
+	res := targetResult{
+		target:           s,
+		rows:             make([][]any, 0, len(finders)),
+		algoMatches:      map[string]int{},
+		algoResultCounts: map[string]int{},
+	}
+
+	sLen := utf8.RuneCountInString(s)
+
+	for i, f := range finders {
+		sd := sdByFinder[i]
+
+		if prog.preferFrequent {
+			prog.sortByFrequency(sd, origOf)
+		}
+
+		res.algoResultCounts[f.Algo.Name()] += len(sd)
+
+		if len(sd) > 0 {
+			res.matched = true
+			res.algoMatches[f.Algo.Name()]++
+		}
+
+		if prog.auditFile != "" {
+			res.auditHits = append(res.auditHits, auditHitsFor(s, f, sd, origOf)...)
+		}
+
+		vals := []any{}
+
+		if prog.compact {
+			vals = append(vals, f.Algo.Name())
+			vals = append(vals, bestResult(sd, origOf)...)
+
+			if prog.showSimilarity {
+				if len(sd) == 0 {
+					vals = append(vals, nil)
+				} else {
+					vals = append(vals, similarityFor(
+						f.Algo.Name(), sd[0].Dist,
+						sLen, utf8.RuneCountInString(sd[0].Str)))
+				}
+			}
+		} else {
+			if prog.showColumn(reportColumnName) {
+				vals = append(vals, f.Algo.Name())
+			}
+
+			if prog.showColumn(reportColumnDetails) && prog.commonCols.algoDetails == nil {
+				vals = append(vals, f.Algo.Desc())
+			}
+
+			if prog.showColumn(reportColumnThreshold) && prog.commonCols.threshold == nil {
+				vals = append(vals, f.FinderConfig.Threshold)
+			}
+
+			if prog.showColumn(reportColumnMinStrLen) &&
+				prog.commonCols.minStrLength == nil {
+				vals = append(vals, f.FinderConfig.MinStrLength)
+			}
+
+			if prog.showColumn(reportColumnMinStrLenAppliesTo) &&
+				prog.commonCols.minStrLenAppliesTo == nil {
+				vals = append(vals, prog.minStrLenModes[f])
+			}
+
+			if prog.showColumn(reportColumnMapToLower) &&
+				prog.commonCols.mapToLowerCase == nil {
+				vals = append(vals, f.FinderConfig.MapToLowerCase)
+			}
+
+			if prog.showColumn(reportColumnStripRunes) && prog.commonCols.stripRunes == nil {
+				vals = append(vals, f.FinderConfig.StripRunes)
+			}
+
+			if prog.showColumn(reportColumnCount) {
+				vals = append(vals, len(sd))
+			}
+
+			for i := range prog.maxResults {
+				if i < len(sd) {
+					sdVal := sd[i]
+					val := sdVal.Str
+
+					if orig, ok := origOf[val]; ok {
+						val = orig
+					}
+
+					vals = append(vals, sdVal.Dist, val)
+
+					if prog.showSimilarity {
+						vals = append(vals, similarityFor(
+							f.Algo.Name(), sdVal.Dist,
+							sLen, utf8.RuneCountInString(sdVal.Str)))
+					}
+				} else {
+					vals = append(vals, nil, nil)
+
+					if prog.showSimilarity {
+						vals = append(vals, nil)
+					}
+				}
+			}
+		}
+
+		if prog.perAlgoBudget > 0 {
+			vals = append(vals, prog.truncation.any(f, group))
+		}
+
+		res.rows = append(res.rows, vals)
+	}
+
+	return res
+}
+
+// computeTargetResults computes the targetResult for every target in
+// targets, dispatching up to targetConcurrency of them to run at once in
+// background goroutines while the returned channel is drained, in order,
+// by the caller. The population is read-only during the search so
+// sharing it across the goroutines is safe.
+func (prog *Prog) computeTargetResults(
+	targets []string, finders []*strdist.Finder, searchPop []string,
+	origOf map[string]string, find findLikeFunc,
+	maxConcurrentAlgos, targetConcurrency int,
+) <-chan targetResult {
+	resultChs := make([]chan targetResult, len(targets))
+	for i := range resultChs {
+		resultChs[i] = make(chan targetResult, 1)
+	}
+
+	go func() {
+		sem := make(chan struct{}, targetConcurrency)
+
+		var wg sync.WaitGroup
+
+		for i, s := range targets {
+			wg.Add(1)
+			sem <- struct{}{}
+
+			go func(i int, s string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				resultChs[i] <- prog.computeTargetResult(
+					s, finders, searchPop, origOf, find, maxConcurrentAlgos)
+			}(i, s)
+		}
+
+		wg.Wait()
+	}()
+
+	out := make(chan targetResult)
+
+	go func() {
+		defer close(out)
+
+		for _, ch := range resultChs {
+			out <- <-ch
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+	"github.com/parquet-go/parquet-go"
+)
+
+// parquetRow is one (target, algorithm, distance, value, rank) tuple as
+// written to the --parquet-file output, one row per match, ranked by
+// ascending distance within its target/algorithm pair. It mirrors flatRow,
+// with an explicit Rank column added since a columnar file has no
+// row-order guarantee for a downstream reader to sort by.
+type parquetRow struct {
+	Target   string  `parquet:"target"`
+	Algo     string  `parquet:"algorithm"`
+	Distance float64 `parquet:"distance"`
+	Value    string  `parquet:"value"`
+	Rank     int     `parquet:"rank"`
+}
+
+// writeParquet computes every (target, algorithm, distance, value) tuple,
+// up to prog.maxResults per target/algorithm pair, and writes them to
+// prog.parquetFile as Parquet rows with an explicit schema, for downstream
+// analysis with tools such as pandas or Spark. It exits with a non-zero
+// status if the file cannot be created or the write fails.
+func (prog *Prog) writeParquet(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	rows := []parquetRow{}
+
+	for _, target := range targets {
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		for _, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, origOf)
+			}
+
+			for i := range prog.maxResults {
+				if i >= len(sd) {
+					break
+				}
+
+				val := sd[i].Str
+				if orig, ok := origOf[val]; ok {
+					val = orig
+				}
+
+				rows = append(rows, parquetRow{
+					Target:   target,
+					Algo:     f.Algo.Name(),
+					Distance: sd[i].Dist,
+					Value:    val,
+					Rank:     i + 1,
+				})
+			}
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Target != rows[j].Target {
+			return rows[i].Target < rows[j].Target
+		}
+
+		if rows[i].Algo != rows[j].Algo {
+			return rows[i].Algo < rows[j].Algo
+		}
+
+		return rows[i].Rank < rows[j].Rank
+	})
+
+	w, err := os.Create(prog.parquetFile)
+	if err != nil {
+		fmt.Println("Couldn't create the parquet file:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+	defer w.Close()
+
+	pw := parquet.NewGenericWriter[parquetRow](w)
+
+	if _, err := pw.Write(rows); err != nil {
+		fmt.Println("Couldn't write the parquet file:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	if err := pw.Close(); err != nil {
+		fmt.Println("Couldn't close the parquet file:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+}
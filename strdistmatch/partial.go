@@ -0,0 +1,49 @@
+package main
+
+import "github.com/nickwells/strdist.mod/v2/strdist"
+
+// partialAlgo wraps a strdist.Algo so that when comparing strings of
+// different lengths it slides the shorter one over the longer one and
+// returns the best (smallest) distance found in any window, rather than
+// penalising the two strings for having different lengths. This mirrors
+// the "partial ratio" mode found in some other fuzzy-matching libraries.
+//
+// This costs one call to the wrapped Dist per window, one more than the
+// difference in rune length between the two strings, so it is
+// significantly more expensive than the
+// plain algorithm, especially against a population of long entries.
+type partialAlgo struct {
+	strdist.Algo
+}
+
+// Dist returns the smallest distance between the shorter of s1 and s2 and
+// any equal-length window of the longer one.
+func (a partialAlgo) Dist(s1, s2 string) float64 {
+	short, long := []rune(s1), []rune(s2)
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+
+	if len(short) == len(long) {
+		return a.Algo.Dist(s1, s2)
+	}
+
+	best := -1.0
+
+	for start := 0; start+len(short) <= len(long); start++ {
+		window := string(long[start : start+len(short)])
+
+		d := a.Algo.Dist(string(short), window)
+		if best < 0 || d < best {
+			best = d
+		}
+	}
+
+	return best
+}
+
+// Desc returns the wrapped algorithm's description, noting that matching is
+// done over the best window of the longer string.
+func (a partialAlgo) Desc() string {
+	return a.Algo.Desc() + " (partial)"
+}
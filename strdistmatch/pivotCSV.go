@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// writePivotCSV computes, for each target, the closest match found by each
+// finder, and writes them to prog.pivotCSVFile as a pair of matrices with
+// targets as rows and algorithms as columns: one of the match distances,
+// one of the matched values. This is a more spreadsheet-friendly shape for
+// cross-algorithm comparison than the long-format --flat-sorted output. A
+// cell for a target with no match under a given algorithm is left blank.
+// It exits with a non-zero status if the file cannot be created or the
+// write fails.
+func (prog *Prog) writePivotCSV(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	header := make([]string, 0, len(finders)+1)
+	header = append(header, "target")
+
+	for _, f := range finders {
+		header = append(header, f.Algo.Name())
+	}
+
+	distRows := make([][]string, 0, len(targets))
+	valRows := make([][]string, 0, len(targets))
+
+	for _, target := range targets {
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		distRow := make([]string, 0, len(finders)+1)
+		valRow := make([]string, 0, len(finders)+1)
+		distRow = append(distRow, target)
+		valRow = append(valRow, target)
+
+		for _, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, origOf)
+			}
+
+			if len(sd) == 0 {
+				distRow = append(distRow, "")
+				valRow = append(valRow, "")
+
+				continue
+			}
+
+			val := sd[0].Str
+			if orig, ok := origOf[val]; ok {
+				val = orig
+			}
+
+			distRow = append(distRow, strconv.FormatFloat(sd[0].Dist, 'f', 4, 64))
+			valRow = append(valRow, val)
+		}
+
+		distRows = append(distRows, distRow)
+		valRows = append(valRows, valRow)
+	}
+
+	w, err := os.Create(prog.pivotCSVFile)
+	if err != nil {
+		fmt.Println("Couldn't create the pivot CSV file:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+	defer w.Close()
+
+	cw := csv.NewWriter(w)
+
+	records := make([][]string, 0, 2*len(targets)+4) //nolint:mnd
+	records = append(records, []string{"distances"}, header)
+	records = append(records, distRows...)
+	records = append(records, []string{}, []string{"values"}, header)
+	records = append(records, valRows...)
+
+	if err := cw.WriteAll(records); err != nil {
+		fmt.Println("Couldn't write the pivot CSV file:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+}
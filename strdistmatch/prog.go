@@ -0,0 +1,1270 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nickwells/col.mod/v4/col"
+	"github.com/nickwells/col.mod/v4/colfmt"
+	"github.com/nickwells/strdist.mod/v2/strdist"
+	"github.com/nickwells/verbose.mod/verbose"
+	"golang.org/x/exp/maps"
+)
+
+// Prog holds program parameters and status
+type Prog struct {
+	exitStatus int
+	stack      *verbose.Stack
+
+	maxResults int
+
+	wordFiles             []string
+	population            []string
+	requestFile           string
+	queryFile             string
+	algoFile              string
+	uniqueQueries         bool
+	dedupPopulation       bool
+	popMinLen             int
+	popMaxLen             int
+	trimPopulation        bool
+	skipComments          bool
+	commentPrefix         string
+	maxLineLen            int
+	outputFile            string
+	outputWriter          io.Writer
+	showTiming            bool
+	timing                *timingTracker
+	benchmark             bool
+	benchmarkReps         int
+	progress              bool
+	ensemble              bool
+	frequencyColumn       int
+	frequencies           map[string]float64
+	preferFrequent        bool
+	transliterate         bool
+	statusFile            string
+	checkSymmetry         bool
+	symmetrise            bool
+	partial               bool
+	emptyCell             string
+	compact               bool
+	tokenise              bool
+	tokenRegex            *regexp.Regexp
+	flatSorted            bool
+	dedupAlgos            bool
+	rawBest               bool
+	withConfidence        bool
+	serveAddr             string
+	detectEquivalent      bool
+	minDistance           float64
+	maxDistance           float64
+	worst                 bool
+	groupByDistance       bool
+	groupBy               groupBy
+	fullGrid              bool
+	measureMemory         bool
+	hideCommonColumns     bool
+	commonCols            commonColumnInfo
+	targetCharset         *regexp.Regexp
+	strict                bool
+	auditFile             string
+	auditRecords          []auditRecord
+	maxConcurrentAlgos    int
+	maxConcurrentTargets  int
+	graphemeClusters      bool
+	graphemeEnc           *graphemeEncoder
+	explainAlgoSyntax     bool
+	whyTarget             string
+	whyWord               string
+	parquetFile           string
+	pivotCSVFile          string
+	minStrLenModes        map[*strdist.Finder]string
+	perAlgoBudget         time.Duration
+	truncation            *truncationTracker
+	useTrie               bool
+	topK                  bool
+	goldFile              string
+	roc                   bool
+	skipEmptyTargets      bool
+	requireMatches        bool
+	sortAlgosBy           sortAlgosBy
+	invert                bool
+	invertAggregate       invertAggregate
+	outputFormat          outputFormat
+	noHeader              bool
+	summary               bool
+	showSimilarity        bool
+	interactive           bool
+	warnDefaults          bool
+	defaultMapToLowerCase bool
+	defaultStripRunes     string
+	columns               []reportColumn
+	transpose             bool
+
+	algoSetter *ListMultiSetter[string, algoParams]
+
+	algoParams []NamedValue[string, algoParams]
+
+	stats runStats
+}
+
+// NewProg returns a new Prog instance with the default values set
+//
+//nolint:mnd
+func NewProg() *Prog {
+	return &Prog{
+		stack: &verbose.Stack{},
+
+		maxResults:         5,
+		sortAlgosBy:        sortAlgosInput,
+		invertAggregate:    invertAggregateMin,
+		outputFormat:       outputFormatTable,
+		groupBy:            groupByTarget,
+		tokenRegex:         defaultTokenRegex,
+		maxConcurrentAlgos: runtime.GOMAXPROCS(0),
+		commentPrefix:      "#",
+		maxLineLen:         defaultMaxLineLen,
+		outputWriter:       os.Stdout,
+		benchmarkReps:      1,
+	}
+}
+
+// SetExitStatus sets the exit status to the new value. It will not do this
+// if the exit status has already been set to a non-zero value.
+func (prog *Prog) SetExitStatus(es int) {
+	if prog.exitStatus == 0 {
+		prog.exitStatus = es
+	}
+}
+
+// ForceExitStatus sets the exit status to the new value. It will do this
+// regardless of the existing exit status value.
+func (prog *Prog) ForceExitStatus(es int) {
+	prog.exitStatus = es
+}
+
+// buildFind assembles the findLikeFunc that every query should be run
+// through, wrapping the plain findLike with whichever of --top-k,
+// --use-trie, a per-finder minStrLenAppliesTo setting, --per-algo-budget
+// and --show-timing are set, in that order; --per-algo-budget replaces
+// whatever was built before it rather than wrapping it. It's shared by the main search
+// path and --serve so that a served query behaves the same as a
+// command-line one.
+func (prog *Prog) buildFind(finders []*strdist.Finder) findLikeFunc {
+	find := findLike
+
+	if prog.topK {
+		k := prog.maxResults
+
+		find = func(f *strdist.Finder, s string, pop []string) []strdist.StrDist {
+			return findLikeTopK(f, s, pop, k)
+		}
+	} else if prog.useTrie {
+		tries := map[*strdist.Finder]*trie{}
+
+		var triesMu sync.Mutex
+
+		find = func(
+			f *strdist.Finder, s string, pop []string,
+		) []strdist.StrDist {
+			triesMu.Lock()
+
+			t, ok := tries[f]
+			if !ok {
+				t = buildTrie(pop,
+					func(w string) string { return prepStr(f.FinderConfig, w) })
+				tries[f] = t
+			}
+
+			triesMu.Unlock()
+
+			return findLikeTrie(f, t, s, pop)
+		}
+	}
+
+	for _, mode := range prog.minStrLenModes {
+		if mode != minStrLenApplyBoth {
+			baseFind := find
+
+			find = func(
+				f *strdist.Finder, s string, pop []string,
+			) []strdist.StrDist {
+				if mode := prog.minStrLenModes[f]; mode != minStrLenApplyBoth {
+					return findLikeMinStrLenSide(f, s, pop, mode)
+				}
+
+				return baseFind(f, s, pop)
+			}
+
+			break
+		}
+	}
+
+	if prog.perAlgoBudget > 0 {
+		prog.truncation = newTruncationTracker()
+
+		find = func(
+			f *strdist.Finder, s string, pop []string,
+		) []strdist.StrDist {
+			dists, truncated := findLikeWithBudget(f, s, pop, prog.perAlgoBudget)
+			if truncated {
+				prog.truncation.mark(f, s)
+			}
+
+			return dists
+		}
+	}
+
+	if prog.showTiming {
+		prog.timing = newTimingTracker()
+		find = prog.timing.timeFind(find)
+	}
+
+	return find
+}
+
+// Run is the starting point for the program, it should be called from main()
+// after the command-line parameters have been parsed. Use the SetExitStatus
+// method to record the exit status and then main can exit with that status.
+func (prog *Prog) Run(searchWords []string) {
+	if prog.explainAlgoSyntax {
+		fmt.Println(prog.algoSetter.ValueGrammar())
+		return
+	}
+
+	if prog.algoFile != "" {
+		if err := prog.readAlgoFile(); err != nil {
+			fmt.Println("Couldn't read the algo file:", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+	}
+
+	if prog.whyTarget != "" {
+		finders := prog.makeFinders(nil)
+		if len(finders) == 0 {
+			fmt.Println("No algorithms are configured")
+			prog.SetExitStatus(1)
+
+			return
+		}
+
+		prog.sortFinders(finders)
+		reportWhy(finders, prog.whyTarget, prog.whyWord, prog.minStrLenModes)
+
+		return
+	}
+
+	if prog.serveAddr != "" {
+		pop := prog.getWords()
+		if len(pop) == 0 {
+			fmt.Println("The population of words to be searched is empty")
+			return
+		}
+
+		finders := prog.makeFinders(pop)
+		if len(finders) == 0 {
+			fmt.Println("No algorithms are configured")
+			prog.SetExitStatus(1)
+
+			return
+		}
+
+		prog.sortFinders(finders)
+
+		find := prog.buildFind(finders)
+
+		if err := prog.serve(pop, finders, find); err != nil {
+			fmt.Println("Couldn't start the server:", err)
+			prog.SetExitStatus(1)
+		}
+
+		return
+	}
+
+	if prog.roc {
+		gold := prog.readGoldFile()
+		if gold == nil {
+			return
+		}
+
+		pop := prog.getWords()
+		if len(pop) == 0 {
+			fmt.Println("The population of words to be searched is empty")
+			return
+		}
+
+		finders := prog.makeFinders(pop)
+		if len(finders) == 0 {
+			fmt.Println("No algorithms are configured")
+			prog.SetExitStatus(1)
+
+			return
+		}
+
+		prog.sortFinders(finders)
+		prog.writeROC(finders, pop, gold)
+
+		return
+	}
+
+	if prog.requestFile != "" {
+		targets, err := prog.applyRequestFile()
+		if err != nil {
+			fmt.Println("Couldn't read the request file:", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+
+		searchWords = targets
+	}
+
+	if prog.queryFile != "" {
+		queries, err := prog.readQueryFile()
+		if err != nil {
+			fmt.Println("Couldn't read the query file:", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+
+		searchWords = append(searchWords, queries...)
+	}
+
+	if prog.uniqueQueries {
+		deduped, dropped := dedupPopulation(searchWords)
+		verbose.Printf("dropped %d duplicate search words\n", dropped)
+
+		searchWords = deduped
+	}
+
+	prog.stats = runStats{
+		targets:          len(searchWords),
+		algoMatchCounts:  map[string]int{},
+		algoResultCounts: map[string]int{},
+	}
+	defer prog.writeStatusFile()
+	defer prog.writeAuditFile()
+
+	if len(searchWords) == 0 && !prog.interactive {
+		fmt.Println("There are no words to search for")
+		return
+	}
+
+	if prog.targetCharset != nil {
+		validated, err := prog.validateTargetCharset(searchWords)
+		if err != nil {
+			fmt.Println(err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+
+		searchWords = validated
+
+		if len(searchWords) == 0 {
+			fmt.Println("There are no words to search for")
+			return
+		}
+	}
+
+	pop := prog.getWords()
+	if len(pop) == 0 {
+		fmt.Println("The population of words to be searched is empty")
+		return
+	}
+
+	searchPop := pop
+
+	var origOf map[string]string
+
+	if prog.transliterate {
+		searchPop, origOf = transliteratePop(pop)
+	}
+
+	if prog.tokenise {
+		searchPop, origOf = prog.tokeniseWords(searchPop, origOf)
+	}
+
+	if prog.graphemeClusters {
+		prog.graphemeEnc = newGraphemeEncoder()
+		searchPop, origOf = prog.graphemeEnc.encodeWords(searchPop, origOf)
+	}
+
+	finders := prog.makeFinders(searchPop)
+	if len(finders) == 0 {
+		fmt.Println("No algorithms are configured")
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	prog.sortFinders(finders)
+
+	find := prog.buildFind(finders)
+
+	if prog.interactive {
+		prog.runInteractive(finders, searchPop, origOf, find)
+		return
+	}
+
+	if prog.checkSymmetry {
+		reportSymmetry(finders, searchPop, searchWords)
+		return
+	}
+
+	if prog.detectEquivalent {
+		prog.reportEquivalentFinders(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.measureMemory {
+		prog.measureMemoryUsage(finders, searchPop, searchWords, find)
+		return
+	}
+
+	if prog.benchmark {
+		prog.runBenchmark(finders, searchPop, searchWords, find)
+		return
+	}
+
+	if prog.rawBest {
+		prog.writeRawBest(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.invert {
+		prog.writeInvertedReport(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.requestFile != "" {
+		prog.writeJSONResults(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.outputFormat == outputFormatJSON {
+		prog.writeOutputJSON(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.outputFormat == outputFormatCSV {
+		prog.writeOutputCSV(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.outputFormat == outputFormatMarkdown {
+		prog.writeOutputMarkdown(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.transpose {
+		prog.writeTransposedReport(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.flatSorted {
+		prog.writeFlatSorted(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.ensemble {
+		prog.writeEnsemble(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.parquetFile != "" {
+		prog.writeParquet(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.pivotCSVFile != "" {
+		prog.writePivotCSV(finders, searchPop, searchWords, origOf, find)
+		return
+	}
+
+	if prog.groupByDistance {
+		prog.writeGroupedByDistance(finders, searchPop, searchWords, find)
+		return
+	}
+
+	maxResultValueLen := max(getMaxStrLen(pop), getMaxStrLen(searchPop))
+
+	if !prog.compact && !prog.fullGrid {
+		if w, isTTY := terminalWidth(); isTTY &&
+			uint(w) < prog.estimateGridWidth(finders, searchWords, maxResultValueLen) {
+			prog.compact = true
+		}
+	}
+
+	if prog.hideCommonColumns && !prog.compact {
+		prog.commonCols = computeCommonColumns(finders, prog.minStrLenModes)
+	}
+
+	if prog.outputFile != "" {
+		f, err := os.Create(prog.outputFile)
+		if err != nil {
+			fmt.Println("Couldn't create the output file:", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+		defer f.Close()
+
+		prog.outputWriter = f
+	}
+
+	rpt := prog.makeReport(finders, searchWords, maxResultValueLen)
+	if rpt == nil {
+		return
+	}
+
+	maxConcurrent := prog.maxConcurrentAlgos
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	targetConcurrency := prog.maxConcurrentTargets
+	if targetConcurrency < 1 {
+		targetConcurrency = 1
+	}
+
+	results := prog.computeTargetResults(
+		searchWords, finders, searchPop, origOf, find,
+		maxConcurrent, targetConcurrency)
+
+	var progress *progressReporter
+	if prog.progress {
+		progress = newProgressReporter(len(searchWords))
+	}
+
+	keptResults := make([]targetResult, 0, len(searchWords))
+
+	for res := range results {
+		if progress != nil {
+			progress.tick()
+		}
+
+		for algo, count := range res.algoMatches {
+			prog.stats.algoMatchCounts[algo] += count
+		}
+
+		for algo, count := range res.algoResultCounts {
+			prog.stats.algoResultCounts[algo] += count
+		}
+
+		prog.auditRecords = append(prog.auditRecords, res.auditHits...)
+
+		if res.matched {
+			prog.stats.targetsMatched++
+		}
+
+		if prog.skipEmptyTargets && !res.matched {
+			continue
+		}
+
+		if prog.groupBy == groupByAlgorithm {
+			keptResults = append(keptResults, res)
+			continue
+		}
+
+		if err := printRowsByTarget(rpt, res); err != nil {
+			fmt.Printf("Cannot print the report: %s\n", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+	}
+
+	if prog.groupBy == groupByAlgorithm {
+		if err := printRowsByAlgorithm(rpt, keptResults); err != nil {
+			fmt.Printf("Cannot print the report: %s\n", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+	}
+
+	if prog.skipEmptyTargets {
+		skipped := prog.stats.targets - prog.stats.targetsMatched
+		fmt.Printf("\n%d target(s) skipped: no matches found\n", skipped)
+	}
+
+	if footer := commonColumnsFooter(prog.commonCols); footer != "" {
+		fmt.Print(footer)
+	}
+
+	if prog.showTiming {
+		prog.writeTimingReport(finders)
+	}
+
+	if prog.summary {
+		prog.writeSummaryReport(finders, len(searchPop))
+	}
+
+	if prog.requireMatches && prog.stats.targetsMatched < prog.stats.targets {
+		prog.SetExitStatus(1)
+	}
+}
+
+// bestResult returns the distance and value of the closest match in sd, or
+// nil, nil if there were no matches. It is used by the --compact report,
+// which only ever shows the top result.
+func bestResult(sd []strdist.StrDist, origOf map[string]string) []any {
+	if len(sd) == 0 {
+		return []any{nil, nil}
+	}
+
+	val := sd[0].Str
+	if orig, ok := origOf[val]; ok {
+		val = orig
+	}
+
+	return []any{sd[0].Dist, val}
+}
+
+// estimateGridWidth returns an approximate rendered width, in characters,
+// of the full (non-compact) report for the given finders and targets.
+// It mirrors the column widths chosen in makeReport, plus a fixed
+// allowance per column for the separator col.Report inserts between
+// columns. It need not be exact: it is only used to decide whether to
+// auto-switch to the --compact layout on a narrow terminal.
+//
+//nolint:mnd
+func (prog *Prog) estimateGridWidth(
+	finders []*strdist.Finder, targets []string, maxResultValueLen uint,
+) uint {
+	const colSep = 2
+
+	maxTargetLen := getMaxStrLen(targets)
+	maxAlgoNameLen := getMaxAlgoNameLen(finders)
+	maxAlgoDetailsLen := getMaxAlgoDescLen(finders)
+	maxStripRunesLen := getMaxStripRunesLen(finders)
+
+	w := maxTargetLen + colSep +
+		maxAlgoNameLen + colSep +
+		maxAlgoDetailsLen + colSep +
+		9 + colSep +
+		7 + colSep +
+		uint(len(minStrLenApplyPopulation)) + colSep + //nolint:gosec
+		4 + colSep +
+		maxStripRunesLen + colSep +
+		3 + colSep
+
+	for range prog.maxResults {
+		w += 8 + colSep + maxResultValueLen + colSep
+	}
+
+	if prog.perAlgoBudget > 0 {
+		w += 5 + colSep
+	}
+
+	return w
+}
+
+// getMaxStrLen returns the maximum length, in runes, of the strings in
+// the slice. Runes rather than bytes are counted so that a population
+// containing accented or CJK words doesn't misalign the colfmt.String
+// columns sized from this value.
+func getMaxStrLen(ss []string) uint {
+	maxLen := 0
+
+	for _, s := range ss {
+		if l := utf8.RuneCountInString(s); l > maxLen {
+			maxLen = l
+		}
+	}
+
+	return uint(maxLen) //nolint:gosec
+}
+
+// getMaxAlgoNameLen returns the maximum length of the Algorithm names
+func getMaxAlgoNameLen(finders []*strdist.Finder) uint {
+	maxLen := 0
+
+	for _, f := range finders {
+		l := len(f.Algo.Name())
+		if l > maxLen {
+			maxLen = l
+		}
+	}
+
+	return uint(maxLen) //nolint:gosec
+}
+
+// getMaxAlgoDescLen returns the maximum length of the Algorithm descriptions
+func getMaxAlgoDescLen(finders []*strdist.Finder) uint {
+	maxLen := 0
+
+	for _, f := range finders {
+		s := f.Algo.Desc()
+		sParts := strings.Split(s, "\n")
+
+		for _, sp := range sParts {
+			l := len(sp)
+			if l > maxLen {
+				maxLen = l
+			}
+		}
+	}
+
+	return uint(maxLen) //nolint:gosec
+}
+
+// getMaxStripRunesLen returns the maximum length, in runes, of the
+// StripRunes value
+func getMaxStripRunesLen(finders []*strdist.Finder) uint {
+	maxLen := 0
+
+	for _, f := range finders {
+		l := utf8.RuneCountInString(f.FinderConfig.StripRunes)
+		if l > maxLen {
+			maxLen = l
+		}
+	}
+
+	return uint(maxLen) //nolint:gosec
+}
+
+// newReportHeader returns a col.Header for the main tabular report,
+// suppressing it entirely when --no-header is set so that runs can be
+// concatenated, or fed straight into another parser, without a header row
+// in the middle of the stream.
+func (prog *Prog) newReportHeader() (*col.Header, error) {
+	if prog.noHeader {
+		return col.NewHeader(col.HdrOptDontPrint)
+	}
+
+	return col.NewHeader()
+}
+
+// makeCompactReport generates a reduced report showing just the target, the
+// algorithm name and the top result's distance and value, for the
+// --compact flag.
+//
+//nolint:mnd
+func (prog *Prog) makeCompactReport(
+	finders []*strdist.Finder,
+	targets []string,
+	maxResultValueLen uint,
+) *col.Report {
+	maxTargetLen := getMaxStrLen(targets)
+	maxAlgoNameLen := getMaxAlgoNameLen(finders)
+
+	if maxAlgoNameLen == 0 {
+		maxAlgoNameLen = 1
+	}
+
+	h, err := prog.newReportHeader()
+	if err != nil {
+		fmt.Printf("Couldn't make the report header: %s\n", err)
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+
+	targetCol := col.New(colfmt.String{W: maxTargetLen}, "target")
+	cols := []*col.Col{
+		col.New(colfmt.String{W: maxAlgoNameLen}, "algorithm"),
+		col.New(
+			withEmptyCell(&colfmt.Float{
+				W:         8,
+				Prec:      4,
+				IgnoreNil: true,
+			}, prog.emptyCell),
+			"best", "distance"),
+		col.New(
+			withEmptyCell(colfmt.String{
+				W:         maxResultValueLen,
+				IgnoreNil: true,
+			}, prog.emptyCell),
+			"best", "value"),
+	}
+
+	if prog.showSimilarity {
+		cols = append(cols, col.New(
+			withEmptyCell(&colfmt.Float{
+				W:         6,
+				Prec:      4,
+				IgnoreNil: true,
+			}, prog.emptyCell),
+			"best", "similarity"))
+	}
+
+	if prog.perAlgoBudget > 0 {
+		cols = append(cols, col.New(colfmt.Bool{}, "results", "truncated"))
+	}
+
+	r, err := col.NewReport(h, prog.outputWriter, targetCol, cols...)
+	if err != nil {
+		fmt.Println("Couldn't create the report:", err)
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+
+	return r
+}
+
+// makeReport generates the report for printing the results of the search
+//
+//nolint:mnd
+func (prog *Prog) makeReport(
+	finders []*strdist.Finder,
+	targets []string,
+	maxResultValueLen uint,
+) *col.Report {
+	if prog.compact {
+		return prog.makeCompactReport(finders, targets, maxResultValueLen)
+	}
+
+	maxTargetLen := getMaxStrLen(targets)
+	maxAlgoNameLen := getMaxAlgoNameLen(finders)
+	maxAlgoDetailsLen := getMaxAlgoDescLen(finders)
+	maxStripRunesLen := getMaxStripRunesLen(finders)
+
+	if maxAlgoNameLen == 0 {
+		maxAlgoNameLen = 1
+	}
+
+	if maxAlgoDetailsLen == 0 {
+		maxAlgoDetailsLen = 1
+	}
+
+	if maxStripRunesLen == 0 {
+		maxStripRunesLen = 1
+	}
+
+	h, err := prog.newReportHeader()
+	if err != nil {
+		fmt.Printf("Couldn't make the report header: %s\n", err)
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+
+	targetCol := col.New(colfmt.String{W: maxTargetLen}, "target")
+	cols := []*col.Col{}
+
+	if prog.showColumn(reportColumnName) {
+		cols = append(cols, col.New(
+			colfmt.String{
+				W: maxAlgoNameLen,
+			},
+			"algorithm", "name"))
+	}
+
+	if prog.showColumn(reportColumnDetails) && prog.commonCols.algoDetails == nil {
+		cols = append(cols, col.New(
+			colfmt.WrappedString{
+				W: maxAlgoDetailsLen,
+			},
+			"algorithm", "details"))
+	}
+
+	if prog.showColumn(reportColumnThreshold) && prog.commonCols.threshold == nil {
+		cols = append(cols, col.New(
+			&colfmt.Float{
+				W:         9,
+				Prec:      5,
+				IgnoreNil: true,
+			},
+			"Finder", "", "threshold"))
+	}
+
+	if prog.showColumn(reportColumnMinStrLen) && prog.commonCols.minStrLength == nil {
+		cols = append(cols, col.New(
+			&colfmt.Int{
+				W:         7,
+				IgnoreNil: true,
+			}, "Finder", "minimum", "str len"))
+	}
+
+	if prog.showColumn(reportColumnMinStrLenAppliesTo) &&
+		prog.commonCols.minStrLenAppliesTo == nil {
+		cols = append(cols, col.New(
+			colfmt.String{
+				W: uint(len(minStrLenApplyPopulation)), //nolint:gosec
+			}, "Finder", "min str len", "applies to"))
+	}
+
+	if prog.showColumn(reportColumnMapToLower) && prog.commonCols.mapToLowerCase == nil {
+		cols = append(cols, col.New(colfmt.Bool{}, "Finder", "map to", "lower"))
+	}
+
+	if prog.showColumn(reportColumnStripRunes) && prog.commonCols.stripRunes == nil {
+		cols = append(cols, col.New(
+			colfmt.String{
+				W:         maxStripRunesLen,
+				IgnoreNil: true,
+			},
+			"Finder", "strip", "runes"))
+	}
+
+	if prog.showColumn(reportColumnCount) {
+		cols = append(cols,
+			col.New(colfmt.Int{W: 3, HandleZeroes: true}, "# of", "results"))
+	}
+
+	for i := range prog.maxResults {
+		commonHeader := fmt.Sprintf("result %d", i+1)
+		cols = append(cols, col.New(
+			withEmptyCell(&colfmt.Float{
+				W:         8,
+				Prec:      4,
+				IgnoreNil: true,
+			}, prog.emptyCell),
+			commonHeader, "distance"))
+		cols = append(cols, col.New(
+			withEmptyCell(colfmt.String{
+				W:         maxResultValueLen,
+				IgnoreNil: true,
+			}, prog.emptyCell),
+			commonHeader, "value"))
+
+		if prog.showSimilarity {
+			cols = append(cols, col.New(
+				withEmptyCell(&colfmt.Float{
+					W:         6,
+					Prec:      4,
+					IgnoreNil: true,
+				}, prog.emptyCell),
+				commonHeader, "similarity"))
+		}
+	}
+
+	if prog.perAlgoBudget > 0 {
+		cols = append(cols, col.New(colfmt.Bool{}, "results", "truncated"))
+	}
+
+	r, err := col.NewReport(h, prog.outputWriter, targetCol, cols...)
+	if err != nil {
+		fmt.Println("Couldn't create the report:", err)
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+
+	return r
+}
+
+// getWords returns a slice containing the population of words to be
+// searched. If prog.population has been set, from a --request-file, that is
+// returned directly. Otherwise it reads each of prog.wordFiles in turn,
+// concatenating their entries into the one population slice, preserving
+// any duplicates across files. It will exit on any error reading one of
+// the files, reporting which file failed.
+func (prog *Prog) getWords() []string {
+	if len(prog.population) > 0 {
+		return prog.population
+	}
+
+	pop := []string{}
+
+	for _, wordFile := range prog.wordFiles {
+		words := prog.readOneWordFile(wordFile)
+		if prog.exitStatus != 0 {
+			return nil
+		}
+
+		verbose.Printf("the population file (%q) holds %d entries\n",
+			wordFile, len(words))
+
+		pop = append(pop, words...)
+	}
+
+	if len(pop) == 0 {
+		fmt.Println("The population of words to search is empty")
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+
+	verbose.Printf("the population holds %d entries in total\n", len(pop))
+
+	if prog.trimPopulation {
+		trimmed, dropped := trimPopulation(pop)
+		verbose.Printf("dropped %d population entries that were blank"+
+			" after trimming\n", dropped)
+
+		pop = trimmed
+	}
+
+	if prog.dedupPopulation {
+		deduped, dropped := dedupPopulation(pop)
+		verbose.Printf("dropped %d duplicate population entries\n", dropped)
+
+		pop = deduped
+	}
+
+	if prog.popMinLen > 0 || prog.popMaxLen > 0 {
+		filtered, dropped := prog.filterPopulationByLength(pop)
+		verbose.Printf("dropped %d population entries outside the length range\n",
+			dropped)
+
+		pop = filtered
+	}
+
+	return pop
+}
+
+// defaultMaxLineLen is the buffer size given to the bufio.Scanner reading
+// a word file, in bytes, well above bufio's own 64KB default so that a
+// population file with a few pathologically long lines doesn't fail with
+// an opaque "token too long" error. See paramNameMaxLineLen to raise it
+// further.
+const defaultMaxLineLen = 1024 * 1024
+
+// utf8BOM is the UTF-8 encoding of U+FEFF, sometimes written by Windows
+// tools at the start of an exported file. readOneWordFile strips it from
+// the first line so it doesn't glue itself onto the first population
+// word and skew its distance.
+const utf8BOM = "\uFEFF"
+
+// readOneWordFile opens wordFile and returns the population words it holds,
+// taking prog.frequencyColumn into account. It sets a non-zero exit
+// status and returns nil on any error, reporting which file failed.
+func (prog *Prog) readOneWordFile(wordFile string) []string {
+	r, err := openWordFile(wordFile)
+	if err != nil {
+		fmt.Println("Failed to open the file of words to search"+
+			" ("+wordFile+"):", err)
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+	defer r.Close()
+
+	if prog.frequencyColumn > 0 {
+		return prog.getWordsWithFrequency(r, wordFile)
+	}
+
+	words := []string{}
+	skipped := 0
+
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), prog.maxLineLen)
+
+	firstLine := true
+
+	for s.Scan() {
+		line := s.Text()
+
+		if firstLine {
+			line = strings.TrimPrefix(line, utf8BOM)
+			firstLine = false
+		}
+
+		if prog.skipComments {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" || strings.HasPrefix(trimmed, prog.commentPrefix) {
+				skipped++
+				continue
+			}
+		}
+
+		words = append(words, line)
+	}
+
+	if err := s.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			fmt.Printf(
+				"Reading the file of words to search (%s):"+
+					" a line is longer than the %d byte limit;"+
+					" raise it with --%s\n",
+				wordFile, prog.maxLineLen, paramNameMaxLineLen)
+			prog.SetExitStatus(1)
+
+			return nil
+		}
+
+		fmt.Println("Reading the file of words to search ("+wordFile+"):", err)
+		prog.SetExitStatus(1)
+
+		return nil
+	}
+
+	if prog.skipComments {
+		verbose.Printf("skipped %d blank or comment lines in %q\n",
+			skipped, wordFile)
+	}
+
+	return words
+}
+
+// finderKey canonicalises an --algo entry for --dedup-algos: two entries
+// with the same name and the same algoParams will always build an
+// identical strdist.Finder, so the second one can just reuse the first.
+type finderKey struct {
+	name   string
+	params algoParams
+}
+
+// makeFinders constructs the Finders from the passed parameters, priming a
+// shared n-gram cache for pop where several entries use the same n-gram
+// config. It will exit on any error. If prog.dedupAlgos is set then entries
+// which canonicalise to the same finderKey as an earlier entry reuse that
+// earlier Finder (and so its n-gram cache) instead of building a new,
+// identical one.
+func (prog *Prog) makeFinders(pop []string) []*strdist.Finder {
+	finders := []*strdist.Finder{}
+	built := map[finderKey]*strdist.Finder{}
+	sharedNGramAlgos := map[ngramGroupKey]strdist.Algo{}
+	prog.minStrLenModes = map[*strdist.Finder]string{}
+
+	groupCounts := map[ngramGroupKey]int{}
+
+	for _, nv := range prog.algoParams {
+		if key, ok := ngramGroupKeyFor(nv.Name, nv.Value); ok {
+			groupCounts[key]++
+		}
+	}
+
+	for _, nv := range prog.algoParams {
+		algoName, algoParams := nv.Name, nv.Value
+
+		key := finderKey{name: algoName, params: algoParams}
+		if prog.dedupAlgos {
+			if f, ok := built[key]; ok {
+				finders = append(finders, f)
+				continue
+			}
+		}
+
+		algoMaker, ok := algoMakers[algoName]
+
+		if !ok {
+			fmt.Printf("Unknown algorithm: %q%s\n", algoName,
+				SuggestAlternatives(3, algoName, maps.Keys(algoMakers)))
+			prog.SetExitStatus(1)
+
+			return nil
+		}
+
+		algo, err := prog.makeSharedableAlgo(
+			algoMaker, algoName, algoParams, pop, groupCounts, sharedNGramAlgos)
+		if err != nil {
+			fmt.Printf("Couldn't make the algo for %q: %s\n", algoName, err)
+			prog.SetExitStatus(1)
+
+			return nil
+		}
+
+		if prog.symmetrise {
+			algo = symmetricAlgo{Algo: algo}
+		}
+
+		if prog.partial {
+			algo = partialAlgo{Algo: algo}
+		}
+
+		threshold := defaultThresholdFor(algoName)
+		if algoParams.useGivenThreshold {
+			threshold = algoParams.threshold
+		} else if prog.warnDefaults {
+			fmt.Fprintf(os.Stderr,
+				"note: %q is using its default threshold (%g);"+
+					" set threshold explicitly to silence this\n",
+				algoName, threshold)
+		}
+
+		mapToLowerCase := algoParams.mapToLowerCase
+		if !algoParams.useGivenMapToLowerCase {
+			mapToLowerCase = prog.defaultMapToLowerCase
+		}
+
+		stripRunes := algoParams.stripRunes
+		if !algoParams.useGivenStripRunes {
+			stripRunes = prog.defaultStripRunes
+		}
+
+		fc := strdist.FinderConfig{
+			Threshold:      threshold,
+			MinStrLength:   algoParams.minStrLen,
+			MapToLowerCase: mapToLowerCase,
+			StripRunes:     stripRunes,
+		}
+
+		f, err := strdist.NewFinder(fc, algo)
+		if err != nil {
+			fmt.Printf("Couldn't make the finder for %q: %s\n", algoName, err)
+			prog.SetExitStatus(1)
+
+			return nil
+		}
+
+		if prog.dedupAlgos {
+			built[key] = f
+		}
+
+		mode := algoParams.minStrLenAppliesTo
+		if mode == "" {
+			mode = minStrLenApplyBoth
+		}
+
+		prog.minStrLenModes[f] = mode
+
+		finders = append(finders, f)
+	}
+
+	return finders
+}
+
+// makeSharedableAlgo builds the Algo for algoName/algoParams, sharing it
+// with other finders that resolve to the same ngramGroupKey (see
+// ngramGroupKeyFor) rather than building an equivalent one from scratch.
+// The first finder in a group primes the shared Algo's n-gram cache with
+// pop, raising its cache size to fit the population if needed, so the
+// n-gram tokenization for pop happens once per distinct config rather than
+// once per finder per target.
+func (prog *Prog) makeSharedableAlgo(
+	algoMaker algoMaker, algoName string, ap algoParams,
+	pop []string, groupCounts map[ngramGroupKey]int,
+	sharedNGramAlgos map[ngramGroupKey]strdist.Algo,
+) (strdist.Algo, error) {
+	gKey, groupable := ngramGroupKeyFor(algoName, ap)
+	if !groupable || groupCounts[gKey] < 2 {
+		return algoMaker(ap)
+	}
+
+	if algo, ok := sharedNGramAlgos[gKey]; ok {
+		verbose.Printf("reusing the shared n-gram cache for %s (n=%d)\n",
+			algoName, gKey.nGramLen)
+
+		return algo, nil
+	}
+
+	if ap.maxNGramCacheSize < len(pop) {
+		ap.maxNGramCacheSize = len(pop)
+	}
+
+	algo, err := algoMaker(ap)
+	if err != nil {
+		return nil, err
+	}
+
+	verbose.Printf(
+		"priming a shared n-gram cache (size %d) for %s (n=%d)"+
+			" across %d finders\n",
+		ap.maxNGramCacheSize, algoName, gKey.nGramLen, groupCounts[gKey])
+
+	for _, w := range pop {
+		algo.Dist(w, w)
+	}
+
+	sharedNGramAlgos[gKey] = algo
+
+	return algo, nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/nickwells/col.mod/v4/col"
+	"github.com/nickwells/col.mod/v4/colfmt"
+)
+
+func TestGetMaxStrLen(t *testing.T) {
+	testCases := []struct {
+		name   string
+		ss     []string
+		expVal uint
+	}{
+		{
+			name:   "ASCII only",
+			ss:     []string{"a", "bb", "ccc"},
+			expVal: 3,
+		},
+		{
+			name:   "multibyte runes have fewer runes than bytes",
+			ss:     []string{"café", "日本語", "hello"},
+			expVal: 5,
+		},
+	}
+
+	for _, tc := range testCases {
+		got := getMaxStrLen(tc.ss)
+		if got != tc.expVal {
+			t.Errorf("%s: getMaxStrLen(%q) == %d, want %d",
+				tc.name, tc.ss, got, tc.expVal)
+		}
+	}
+}
+
+func TestGetMaxStrLenAlignment(t *testing.T) {
+	targets := []string{"日本語", "café", "x"}
+
+	maxTargetLen := getMaxStrLen(targets)
+	if maxTargetLen != 4 {
+		t.Fatalf("getMaxStrLen(%q) == %d, want 4 (runes, not bytes)",
+			targets, maxTargetLen)
+	}
+
+	h, err := col.NewHeader()
+	if err != nil {
+		t.Fatalf("col.NewHeader: %s", err)
+	}
+
+	targetCol := col.New(colfmt.String{W: maxTargetLen}, "target")
+
+	var buf bytes.Buffer
+
+	r, err := col.NewReport(h, &buf, targetCol)
+	if err != nil {
+		t.Fatalf("col.NewReport: %s", err)
+	}
+
+	for _, target := range targets {
+		if err := r.PrintRow(target); err != nil {
+			t.Fatalf("PrintRow(%q): %s", target, err)
+		}
+	}
+
+	const headerRows = 2 // the column name and its underline
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(targets)+headerRows {
+		t.Fatalf("got %d lines, want %d (%d header + %d rows)",
+			len(lines), len(targets)+headerRows, headerRows, len(targets))
+	}
+
+	headerRunes := utf8.RuneCountInString(lines[0])
+
+	for i, line := range lines[headerRows:] {
+		if got := utf8.RuneCountInString(line); got != headerRunes {
+			t.Errorf("row %d (%q): %d runes wide, want %d (header width)",
+				i, targets[i], got, headerRunes)
+		}
+	}
+}
+
+func TestReadOneWordFileBOM(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expWords []string
+	}{
+		{
+			name:     "with a leading BOM",
+			content:  utf8BOM + "alpha\nbeta\n",
+			expWords: []string{"alpha", "beta"},
+		},
+		{
+			name:     "without a leading BOM",
+			content:  "alpha\nbeta\n",
+			expWords: []string{"alpha", "beta"},
+		},
+	}
+
+	for _, tc := range testCases {
+		wordFile := filepath.Join(t.TempDir(), "words.txt")
+		if err := os.WriteFile(wordFile, []byte(tc.content), 0o600); err != nil {
+			t.Fatalf("%s: couldn't write the word file: %s", tc.name, err)
+		}
+
+		prog := NewProg()
+
+		got := prog.readOneWordFile(wordFile)
+		if prog.exitStatus != 0 {
+			t.Fatalf("%s: readOneWordFile set a non-zero exit status", tc.name)
+		}
+
+		if len(got) != len(tc.expWords) {
+			t.Fatalf("%s: got %d words: %q, want %q",
+				tc.name, len(got), got, tc.expWords)
+		}
+
+		for i, w := range got {
+			if w != tc.expWords[i] {
+				t.Errorf("%s: word %d == %q, want %q", tc.name, i, w, tc.expWords[i])
+			}
+		}
+	}
+}
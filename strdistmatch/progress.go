@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// progressReporter writes an occasional "N/total targets processed" line
+// to stderr for --progress, so a run against a large population gives
+// some feedback. It is throttled to roughly once a second rather than
+// once per target, so it doesn't itself become the bottleneck.
+type progressReporter struct {
+	total    int
+	done     int
+	lastShow time.Time
+}
+
+// newProgressReporter returns a progressReporter for a run of total targets.
+func newProgressReporter(total int) *progressReporter {
+	return &progressReporter{total: total}
+}
+
+// tick records that one more target has been processed and, if at least a
+// second has passed since the last report, writes a progress line to
+// stderr.
+func (p *progressReporter) tick() {
+	p.done++
+
+	now := time.Now()
+	if p.done < p.total && now.Sub(p.lastShow) < time.Second {
+		return
+	}
+
+	p.lastShow = now
+
+	fmt.Fprintf(os.Stderr, "%d/%d targets processed\n", p.done, p.total)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bufio"
+	"os"
+)
+
+// readQueryFile reads prog.queryFile and returns its non-blank lines as
+// search terms, to be combined with any remainder arguments. This lets a
+// caller with thousands of query strings avoid hitting ARG_MAX by putting
+// them in a file instead of passing them on the command line.
+func (prog *Prog) readQueryFile() ([]string, error) {
+	f, err := os.Open(prog.queryFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	queries := []string{}
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" {
+			continue
+		}
+
+		queries = append(queries, line)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return queries, nil
+}
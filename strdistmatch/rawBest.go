@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// writeRawBest prints, for a single target, only the best match found by
+// the first (or, with --sort-algos, first after sorting) finder: no
+// decoration, newline-terminated, and nothing else on stdout. This is
+// meant for shell substitution, e.g. best=$(strdistmatch ... word), so
+// the tool can be used as a spell-corrector inline in a script. Errors and
+// diagnostics go to stderr; if there is no match, nothing is printed and
+// the exit status is set non-zero. With --with-confidence a tab and a
+// confidence score are appended to the line, still with nothing else on
+// stdout, so a caller that doesn't want it can just ignore the second
+// field.
+func (prog *Prog) writeRawBest(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	if len(targets) != 1 {
+		fmt.Fprintln(os.Stderr,
+			"--raw-best requires exactly one target, got", len(targets))
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	if len(finders) == 0 {
+		fmt.Fprintln(os.Stderr, "--raw-best requires at least one "+
+			paramNameAlgo+" to be configured")
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	group := parseTargetGroup(targets[0])
+	if prog.transliterate {
+		group = transliterateGroup(group)
+	}
+
+	if prog.tokenise {
+		group = tokeniseGroup(prog.tokenRegex, group)
+	}
+
+	if prog.graphemeClusters {
+		group = prog.graphemeEnc.encodeGroup(group)
+	}
+
+	sd := prog.filterMinDistance(findLikeGroup(finders[0], group, searchPop, find))
+
+	if prog.preferFrequent {
+		prog.sortByFrequency(sd, origOf)
+	}
+
+	if len(sd) == 0 {
+		prog.SetExitStatus(1)
+		return
+	}
+
+	val := sd[0].Str
+	if orig, ok := origOf[val]; ok {
+		val = orig
+	}
+
+	if prog.withConfidence {
+		fmt.Printf("%s\t%.4f\n", val, confidence(sd))
+		return
+	}
+
+	fmt.Println(val)
+}
@@ -0,0 +1,43 @@
+package main
+
+// reportColumn identifies one of the optional Finder-configuration columns
+// in the full (non-compact) report, so that --columns can select a subset
+// of them. The target column and the per-result distance/value columns are
+// not identified this way as they are always shown.
+type reportColumn string
+
+const (
+	reportColumnName               reportColumn = "name"
+	reportColumnDetails            reportColumn = "details"
+	reportColumnThreshold          reportColumn = "threshold"
+	reportColumnMinStrLen          reportColumn = "min-str-len"
+	reportColumnMinStrLenAppliesTo reportColumn = "min-str-len-applies-to"
+	reportColumnMapToLower         reportColumn = "map-to-lower"
+	reportColumnStripRunes         reportColumn = "strip-runes"
+	reportColumnCount              reportColumn = "count"
+)
+
+// showColumn reports whether c should appear in the report, given
+// prog.columns. A nil/empty prog.columns means every column is shown, the
+// default when --columns hasn't been given. reportColumnName is always
+// shown when prog.groupBy is groupByAlgorithm, regardless of --columns:
+// printRowsByAlgorithm relies on that column, at a fixed index, both to
+// label each algorithm's block and to blank out the label on every row
+// after the block's first.
+func (prog *Prog) showColumn(c reportColumn) bool {
+	if c == reportColumnName && prog.groupBy == groupByAlgorithm {
+		return true
+	}
+
+	if len(prog.columns) == 0 {
+		return true
+	}
+
+	for _, want := range prog.columns {
+		if want == c {
+			return true
+		}
+	}
+
+	return false
+}
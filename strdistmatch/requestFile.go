@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// jsonAlgoSpec is the JSON form of an algorithm entry in a --request-file
+// document. It mirrors the sub-values accepted by the --algo parameter;
+// zero-valued fields take the same defaults as --algo.
+type jsonAlgoSpec struct {
+	Name           string   `json:"name"`
+	NGramLen       int      `json:"nGramLen"`
+	MinNGramLen    int      `json:"minNGramLen"`
+	OverflowNGrams bool     `json:"overflowNGrams"`
+	NGramStep      int      `json:"ngramStep"`
+	Threshold      *float64 `json:"threshold"`
+	MinStrLen      int      `json:"minStrLen"`
+	MapToLowerCase *bool    `json:"mapToLowerCase"`
+	StripRunes     *string  `json:"stripRunes"`
+}
+
+// jsonRequest is the document read from --request-file: a self-contained
+// description of the population, the algorithms and the targets for a
+// single invocation.
+type jsonRequest struct {
+	WordFile   string         `json:"wordFile"`
+	Population []string       `json:"population"`
+	Algos      []jsonAlgoSpec `json:"algos"`
+	Targets    []string       `json:"targets"`
+	MaxResults int            `json:"maxResults"`
+}
+
+// jsonHit is a single match reported against one algorithm.
+type jsonHit struct {
+	Value    string  `json:"value"`
+	Distance float64 `json:"distance"`
+}
+
+// jsonResult is one target's entry in the --request-file response, giving
+// the matches found by each algorithm, keyed by algorithm name.
+type jsonResult struct {
+	Target  string               `json:"target"`
+	Matches map[string][]jsonHit `json:"matches"`
+}
+
+// toAlgoParams converts a jsonAlgoSpec into an algoParams, taking any
+// unset numeric fields from dflt. mapToLowerCase, stripRunes and
+// threshold are pointers so that an explicitly-given value can be told
+// apart from one left unset, just as with the equivalent --algo subvals:
+// leaving them unset lets --default-to-lower/--default-strip-runes apply.
+func (spec jsonAlgoSpec) toAlgoParams(dflt algoParams) algoParams {
+	ap := dflt
+
+	if spec.NGramLen != 0 {
+		ap.nGramLen = spec.NGramLen
+	}
+
+	ap.minNGramLen = spec.MinNGramLen
+	ap.overflowTheSource = spec.OverflowNGrams
+
+	if spec.NGramStep != 0 {
+		ap.nGramStep = spec.NGramStep
+	}
+
+	ap.minStrLen = spec.MinStrLen
+
+	if spec.MapToLowerCase != nil {
+		ap.mapToLowerCase = *spec.MapToLowerCase
+		ap.useGivenMapToLowerCase = true
+	}
+
+	if spec.StripRunes != nil {
+		ap.stripRunes = *spec.StripRunes
+		ap.useGivenStripRunes = true
+	}
+
+	if spec.Threshold != nil {
+		ap.threshold = *spec.Threshold
+		ap.useGivenThreshold = true
+	}
+
+	return ap
+}
+
+// applyRequestFile reads the JSON document at prog.requestFile and uses it
+// to populate prog's word-file/population, algorithms and maximum results,
+// returning the targets to search for.
+func (prog *Prog) applyRequestFile() ([]string, error) {
+	f, err := os.Open(prog.requestFile)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var req jsonRequest
+
+	if err := json.NewDecoder(f).Decode(&req); err != nil {
+		return nil, fmt.Errorf("couldn't parse the request file: %w", err)
+	}
+
+	if req.WordFile != "" {
+		prog.wordFiles = []string{req.WordFile}
+	}
+
+	if len(req.Population) > 0 {
+		prog.population = req.Population
+	}
+
+	if req.MaxResults > 0 {
+		prog.maxResults = req.MaxResults
+	}
+
+	dflt := algoParams{
+		nGramLen:           dfltNGramLen,
+		nGramStep:          dfltNGramStep,
+		maxNGramCacheSize:  dfltMaxNGramCacheSize,
+		minStrLenAppliesTo: minStrLenApplyBoth,
+	}
+
+	if len(req.Algos) > 0 {
+		algos := make([]NamedValue[string, algoParams], 0, len(req.Algos))
+
+		for _, spec := range req.Algos {
+			ap := spec.toAlgoParams(dflt)
+
+			if ap.useGivenThreshold {
+				if err := checkThreshold(spec.Name, ap.threshold); err != nil {
+					return nil, err
+				}
+			}
+
+			algos = append(algos, NamedValue[string, algoParams]{
+				Name:  spec.Name,
+				Value: ap,
+			})
+		}
+
+		prog.algoParams = algos
+	}
+
+	return req.Targets, nil
+}
+
+// writeJSONResults writes the results of searching for each of the targets
+// against searchPop, using the given finders, as a JSON array to
+// stdout. origOf, if non-nil, maps a (possibly transliterated) population
+// value back to the original word to report.
+func (prog *Prog) writeJSONResults(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	results := make([]jsonResult, 0, len(targets))
+
+	for _, target := range targets {
+		res := jsonResult{Target: target, Matches: map[string][]jsonHit{}}
+
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		for _, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, origOf)
+			}
+
+			hits := make([]jsonHit, 0, min(len(sd), prog.maxResults))
+
+			for i := range prog.maxResults {
+				if i >= len(sd) {
+					break
+				}
+
+				val := sd[i].Str
+				if orig, ok := origOf[val]; ok {
+					val = orig
+				}
+
+				hits = append(hits, jsonHit{Value: val, Distance: sd[i].Dist})
+			}
+
+			res.Matches[f.Algo.Name()] = hits
+		}
+
+		results = append(results, res)
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(results); err != nil {
+		fmt.Println("Couldn't write the JSON results:", err)
+		prog.SetExitStatus(1)
+	}
+}
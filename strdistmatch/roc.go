@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// rocConfusion accumulates the true/false-positive counts, across every
+// (target, population word) pair for one algorithm, at one threshold.
+type rocConfusion struct {
+	tp, fn, fp, tn int
+}
+
+// tpr returns the true-positive rate: of the pairs where the population
+// word actually is the target's gold answer, the fraction predicted
+// positive.
+func (c rocConfusion) tpr() float64 {
+	if c.tp+c.fn == 0 {
+		return 0
+	}
+
+	return float64(c.tp) / float64(c.tp+c.fn)
+}
+
+// fpr returns the false-positive rate: of the pairs where the population
+// word is not the target's gold answer, the fraction wrongly predicted
+// positive.
+func (c rocConfusion) fpr() float64 {
+	if c.fp+c.tn == 0 {
+		return 0
+	}
+
+	return float64(c.fp) / float64(c.fp+c.tn)
+}
+
+// writeROC computes, for each finder and a threshold at every distance
+// value observed between a gold target and a population word, the
+// resulting true- and false-positive rates, and writes them as CSV
+// (algorithm,threshold,tpr,fpr) to stdout. It sweeps every observed
+// distance rather than an arbitrary step count, so the curve it traces out
+// is exact rather than a sampled approximation of one.
+func (prog *Prog) writeROC(
+	finders []*strdist.Finder, pop []string, gold map[string]string,
+) {
+	targets := make([]string, 0, len(gold))
+	for t := range gold {
+		targets = append(targets, t)
+	}
+
+	sort.Strings(targets)
+
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	_ = w.Write([]string{"algorithm", "threshold", "tpr", "fpr"})
+
+	for _, f := range finders {
+		prog.writeROCForFinder(w, f, pop, targets, gold)
+	}
+
+	if err := w.Error(); err != nil {
+		fmt.Println("Couldn't write the ROC data:", err)
+		prog.SetExitStatus(1)
+	}
+}
+
+// writeROCForFinder writes one finder's ROC points to w, one CSV row per
+// distinct distance observed between a target and a population word.
+func (prog *Prog) writeROCForFinder(
+	w *csv.Writer, f *strdist.Finder, pop, targets []string,
+	gold map[string]string,
+) {
+	dists := map[[2]string]float64{}
+	seenThresholds := map[float64]bool{}
+
+	for _, target := range targets {
+		prepTarget := prepStr(f.FinderConfig, target)
+
+		for _, p := range pop {
+			d := f.Algo.Dist(prepTarget, prepStr(f.FinderConfig, p))
+			dists[[2]string{target, p}] = d
+			seenThresholds[d] = true
+		}
+	}
+
+	thresholds := make([]float64, 0, len(seenThresholds))
+	for t := range seenThresholds {
+		thresholds = append(thresholds, t)
+	}
+
+	sort.Float64s(thresholds)
+
+	for _, threshold := range thresholds {
+		c := confusionAt(threshold, targets, pop, gold, dists)
+
+		_ = w.Write([]string{
+			f.Algo.Name(),
+			fmt.Sprintf("%.5f", threshold),
+			fmt.Sprintf("%.5f", c.tpr()),
+			fmt.Sprintf("%.5f", c.fpr()),
+		})
+	}
+}
+
+// confusionAt computes the confusion counts, across every (target,
+// population word) pair, of predicting a match whenever its recorded
+// distance is at most threshold.
+func confusionAt(
+	threshold float64, targets, pop []string, gold map[string]string,
+	dists map[[2]string]float64,
+) rocConfusion {
+	var c rocConfusion
+
+	for _, target := range targets {
+		goldWord := gold[target]
+
+		for _, p := range pop {
+			isGold := p == goldWord
+			predictedPositive := dists[[2]string{target, p}] <= threshold
+
+			switch {
+			case isGold && predictedPositive:
+				c.tp++
+			case isGold && !predictedPositive:
+				c.fn++
+			case !isGold && predictedPositive:
+				c.fp++
+			default:
+				c.tn++
+			}
+		}
+	}
+
+	return c
+}
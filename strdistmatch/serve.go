@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// servePopulation guards the population served by --serve behind a
+// mutex, so that a SIGHUP-triggered reload can swap it out for a fresh
+// one while queries are in flight without corrupting either.
+type servePopulation struct {
+	mu  sync.RWMutex
+	pop []string
+}
+
+// get returns the current population.
+func (sp *servePopulation) get() []string {
+	sp.mu.RLock()
+	defer sp.mu.RUnlock()
+
+	return sp.pop
+}
+
+// set replaces the current population with pop.
+func (sp *servePopulation) set(pop []string) {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+
+	sp.pop = pop
+}
+
+// serve starts a line-oriented TCP server at prog.serveAddr and runs it
+// until the process is killed: each line read from a connection is
+// treated as a single target word, and the matches from every finder
+// are written back, one per line as "algorithm\tdistance\tvalue",
+// followed by a blank line marking the end of that target's results, up
+// to --max-results per finder, using find the same way the main search
+// path does so that --top-k, --use-trie, minStrLenAppliesTo and
+// --per-algo-budget apply to served queries too.
+// While running, it reloads its population from --word-file whenever
+// the process receives SIGHUP, so an evolving dictionary can be picked
+// up without restarting the daemon.
+func (prog *Prog) serve(
+	pop []string, finders []*strdist.Finder, find findLikeFunc,
+) error {
+	sp := &servePopulation{pop: pop}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		for range hup {
+			reloaded, err := prog.readWordFile()
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "reload failed:", err)
+				continue
+			}
+
+			sp.set(reloaded)
+			fmt.Fprintf(os.Stderr,
+				"reloaded population from %v: %d words\n",
+				prog.wordFiles, len(reloaded))
+		}
+	}()
+
+	ln, err := net.Listen("tcp", prog.serveAddr)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+
+	fmt.Fprintln(os.Stderr, "serving on", prog.serveAddr)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "accept failed:", err)
+			continue
+		}
+
+		go prog.serveConn(conn, sp, finders, find)
+	}
+}
+
+// serveConn answers queries on conn until the client closes it or a
+// line can't be read, using the population currently held by sp.
+func (prog *Prog) serveConn(
+	conn net.Conn, sp *servePopulation, finders []*strdist.Finder,
+	find findLikeFunc,
+) {
+	defer conn.Close()
+
+	w := bufio.NewWriter(conn)
+	defer w.Flush()
+
+	s := bufio.NewScanner(conn)
+	for s.Scan() {
+		pop := sp.get()
+		query := s.Text()
+
+		for _, f := range finders {
+			sd := prog.filterMinDistance(find(f, query, pop))
+
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, nil)
+			}
+
+			for i, d := range sd {
+				if i >= prog.maxResults {
+					break
+				}
+
+				fmt.Fprintf(w, "%s\t%.4f\t%s\n", f.Algo.Name(), d.Dist, d.Str)
+			}
+		}
+
+		fmt.Fprintln(w)
+		w.Flush()
+	}
+}
+
+// readWordFile re-reads prog.wordFiles from disk, independent of
+// getWords, so that a failed reload during --serve can be reported and
+// ignored, leaving the daemon serving its last-known-good population,
+// rather than aborting it.
+func (prog *Prog) readWordFile() ([]string, error) {
+	pop := []string{}
+
+	for _, wordFile := range prog.wordFiles {
+		r, err := openWordFile(wordFile)
+		if err != nil {
+			return nil, err
+		}
+
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			pop = append(pop, s.Text())
+		}
+
+		err = s.Err()
+		r.Close()
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(pop) == 0 {
+		return nil, fmt.Errorf("%v is empty", prog.wordFiles)
+	}
+
+	return pop, nil
+}
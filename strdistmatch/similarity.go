@@ -0,0 +1,59 @@
+package main
+
+import "github.com/nickwells/strdist.mod/v2/strdist"
+
+// distNormalizer scales a raw Dist value, given the rune lengths of the
+// two compared strings, into a distance in [0,1].
+type distNormalizer func(dist float64, sLen, pLen int) float64
+
+// distNormalizers holds the distNormalizer for each algorithm whose raw
+// Dist isn't already scaled to [0,1]. Algorithms not listed here (cosine,
+// jaccard, weighted-jaccard, scaled-Levenshtein) already return a
+// distance in that range and need no scaling, so similarityFor leaves
+// their Dist unchanged.
+var distNormalizers = map[string]distNormalizer{
+	strdist.AlgoNameLevenshtein: maxLenNormalizer,
+	strdist.AlgoNameHamming:     maxLenNormalizer,
+	AlgoNameDamerauLevenshtein:  maxLenNormalizer,
+}
+
+// maxLenNormalizer divides dist by the longer of the two compared
+// strings, so that an edit-distance-style Dist, which grows with string
+// length, is scaled into [0,1]. Two zero-length strings are taken as
+// identical.
+func maxLenNormalizer(dist float64, sLen, pLen int) float64 {
+	maxLen := sLen
+	if pLen > maxLen {
+		maxLen = pLen
+	}
+
+	if maxLen == 0 {
+		return 0
+	}
+
+	return dist / float64(maxLen)
+}
+
+// similarityFor returns dist, the raw distance a finder using algoName
+// found between two strings of rune length sLen and pLen, as a
+// normalized similarity in [0,1], most similar at 1. The normalization
+// applied is chosen by algoName; algorithms not in distNormalizers
+// default to identity, treating dist as already a normalized distance,
+// which holds for every algorithm this tool ships except Levenshtein,
+// Damerau-Levenshtein and Hamming.
+func similarityFor(algoName string, dist float64, sLen, pLen int) float64 {
+	if norm, ok := distNormalizers[algoName]; ok {
+		dist = norm(dist, sLen, pLen)
+	}
+
+	sim := 1 - dist
+
+	switch {
+	case sim < 0:
+		return 0
+	case sim > 1:
+		return 1
+	default:
+		return sim
+	}
+}
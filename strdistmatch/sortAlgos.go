@@ -0,0 +1,36 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// sortAlgosBy names the ordering that --sort-algos should apply to the
+// finders before they are used, in place of the default order in which
+// their --algo parameters were given.
+type sortAlgosBy string
+
+const (
+	sortAlgosInput     sortAlgosBy = "input"
+	sortAlgosName      sortAlgosBy = "name"
+	sortAlgosThreshold sortAlgosBy = "threshold"
+)
+
+// sortFinders reorders finders in place according to prog.sortAlgosBy. It
+// uses a stable sort so that, for sortAlgosInput or where two finders tie
+// on the chosen key, the original --algo order is preserved.
+func (prog *Prog) sortFinders(finders []*strdist.Finder) {
+	switch prog.sortAlgosBy {
+	case sortAlgosName:
+		sort.SliceStable(finders, func(i, j int) bool {
+			return finders[i].Algo.Name() < finders[j].Algo.Name()
+		})
+	case sortAlgosThreshold:
+		sort.SliceStable(finders, func(i, j int) bool {
+			return finders[i].FinderConfig.Threshold <
+				finders[j].FinderConfig.Threshold
+		})
+	case sortAlgosInput:
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// runStats accumulates the counts written to the --status-file. It is
+// updated as Run progresses so that a summary is available even if Run
+// returns early.
+type runStats struct {
+	targets          int
+	targetsMatched   int
+	algoMatchCounts  map[string]int
+	algoResultCounts map[string]int
+}
+
+// statusSummary is the document written to the --status-file, giving a
+// machine-readable summary of the outcome of a single run.
+type statusSummary struct {
+	Targets          int            `json:"targets"`
+	TargetsMatched   int            `json:"targetsMatched"`
+	TargetsUnmatched int            `json:"targetsUnmatched"`
+	AlgoMatchCounts  map[string]int `json:"algoMatchCounts"`
+	ExitStatus       int            `json:"exitStatus"`
+}
+
+// writeStatusFile writes prog's accumulated runStats to the configured
+// --status-file, if any. It is called from a defer in Run so that it
+// runs on every exit path, including early returns, giving a
+// controlling process a status to inspect even after a partial run.
+func (prog *Prog) writeStatusFile() {
+	if prog.statusFile == "" {
+		return
+	}
+
+	summary := statusSummary{
+		Targets:          prog.stats.targets,
+		TargetsMatched:   prog.stats.targetsMatched,
+		TargetsUnmatched: prog.stats.targets - prog.stats.targetsMatched,
+		AlgoMatchCounts:  prog.stats.algoMatchCounts,
+		ExitStatus:       prog.exitStatus,
+	}
+
+	f, err := os.Create(prog.statusFile)
+	if err != nil {
+		fmt.Println("Couldn't create the status file:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(summary); err != nil {
+		fmt.Println("Couldn't write the status file:", err)
+		prog.SetExitStatus(1)
+	}
+}
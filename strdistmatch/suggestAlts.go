@@ -0,0 +1,50 @@
+package main
+
+import (
+	"github.com/nickwells/english.mod/english"
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// SuggestAlternatives searches the population for the closest matches to the
+// passed string and if any are found it returns a string suggesting the
+// alternative values. It uses the cosine algorithm; call
+// SuggestAlternativesWith to choose a different one.
+func SuggestAlternatives(n int, s string, pop []string) string {
+	finder := strdist.DefaultFinders[strdist.CaseBlindAlgoNameCosine]
+
+	return SuggestAlternativesWith(finder, n, s, pop)
+}
+
+// SuggestAlternativesWith behaves as SuggestAlternatives but searches using
+// the given finder rather than always defaulting to cosine. This lets
+// callers matching very short strings, such as subvalue names, choose an
+// algorithm such as Levenshtein that behaves better on them.
+func SuggestAlternativesWith(finder *strdist.Finder, n int, s string, pop []string) string {
+	dists := SuggestAlternativesDists(finder, n, s, pop)
+	if len(dists) == 0 {
+		return ""
+	}
+
+	alts := make([]string, 0, len(dists))
+	for _, d := range dists {
+		alts = append(alts, d.Str)
+	}
+
+	return `, did you mean "` + english.Join(alts, `", "`, `" or "`) + `"`
+}
+
+// SuggestAlternativesDists searches the population for the closest matches
+// to the passed string using the given finder and returns up to n of them
+// as StrDists, most similar first, so that callers can show the distance
+// alongside each suggestion (e.g. `did you mean "foo" (0.12)`). It returns
+// nil if there are no close enough matches.
+func SuggestAlternativesDists(
+	finder *strdist.Finder, n int, s string, pop []string,
+) []strdist.StrDist {
+	dists := finder.FindLike(s, pop...)
+	if len(dists) > n {
+		dists = dists[:n]
+	}
+
+	return dists
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nickwells/col.mod/v4/col"
+	"github.com/nickwells/col.mod/v4/colfmt"
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// writeSummaryReport prints a one-line total (targets searched and
+// population size) followed by a small aggregate table, keyed by
+// algorithm, of the number of results returned and the number of targets
+// that got at least one match, for --summary. The counts are those
+// accumulated in prog.stats during the main loop, so no extra search pass
+// is needed. It is printed after the main results table and the
+// --show-timing report, if any, so it never displaces a result column.
+//
+//nolint:mnd
+func (prog *Prog) writeSummaryReport(finders []*strdist.Finder, popSize int) {
+	fmt.Fprintf(prog.outputWriter,
+		"\n%d target(s) searched against a population of %d\n",
+		prog.stats.targets, popSize)
+
+	maxAlgoNameLen := getMaxAlgoNameLen(finders)
+	if maxAlgoNameLen == 0 {
+		maxAlgoNameLen = 1
+	}
+
+	h, err := col.NewHeader()
+	if err != nil {
+		fmt.Printf("Couldn't make the summary report header: %s\n", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	algoCol := col.New(colfmt.String{W: maxAlgoNameLen}, "algorithm")
+	cols := []*col.Col{
+		col.New(colfmt.Int{W: 10, HandleZeroes: true}, "results", "found"),
+		col.New(colfmt.Int{W: 10, HandleZeroes: true}, "targets", "matched"),
+	}
+
+	r, err := col.NewReport(h, prog.outputWriter, algoCol, cols...)
+	if err != nil {
+		fmt.Println("Couldn't create the summary report:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	seen := map[string]bool{}
+
+	for _, f := range finders {
+		name := f.Algo.Name()
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+
+		err := r.PrintRow(
+			name,
+			prog.stats.algoResultCounts[name],
+			prog.stats.algoMatchCounts[name])
+		if err != nil {
+			fmt.Printf("Cannot print the summary report: %s\n", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+	}
+}
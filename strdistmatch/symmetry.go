@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// symmetryTolerance is the largest difference between dist(a,b) and
+// dist(b,a) that is still treated as symmetric, allowing for floating-point
+// rounding.
+const symmetryTolerance = 1e-9
+
+// symmetricAlgo wraps a strdist.Algo so that its distance is always
+// symmetric, averaging both directions. This is useful for algorithms such
+// as weighted Jaccard which do not otherwise guarantee
+// dist(a,b) == dist(b,a).
+type symmetricAlgo struct {
+	strdist.Algo
+}
+
+// Dist returns the average of the wrapped algorithm's distance in each
+// direction.
+func (a symmetricAlgo) Dist(s1, s2 string) float64 {
+	return (a.Algo.Dist(s1, s2) + a.Algo.Dist(s2, s1)) / 2
+}
+
+// Desc returns the wrapped algorithm's description, noting that the
+// distance has been symmetrised.
+func (a symmetricAlgo) Desc() string {
+	return a.Algo.Desc() + " (symmetrised)"
+}
+
+// reportSymmetry prints, for each finder, whether its algorithm is
+// symmetric over the sample pairs formed from the search targets and the
+// population, so that users of an asymmetric algorithm such as weighted
+// Jaccard aren't caught out by it. No search is performed.
+func reportSymmetry(finders []*strdist.Finder, pop, targets []string) {
+	for _, f := range finders {
+		asymmetric := false
+		maxDiff := 0.0
+
+		var worstPair [2]string
+
+		for _, t := range targets {
+			for _, p := range pop {
+				d1 := f.Algo.Dist(t, p)
+				d2 := f.Algo.Dist(p, t)
+
+				diff := d1 - d2
+				if diff < 0 {
+					diff = -diff
+				}
+
+				if diff > maxDiff {
+					maxDiff = diff
+					worstPair = [2]string{t, p}
+				}
+
+				if diff > symmetryTolerance {
+					asymmetric = true
+				}
+			}
+		}
+
+		if !asymmetric {
+			fmt.Printf("%s: symmetric over %d target(s) and %d population word(s)\n",
+				f.Algo.Name(), len(targets), len(pop))
+			continue
+		}
+
+		fmt.Printf(
+			"%s: asymmetric, max difference %.5f for (%q, %q)\n",
+			f.Algo.Name(), maxDiff, worstPair[0], worstPair[1])
+	}
+}
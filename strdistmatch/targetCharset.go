@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// validateTargetCharset checks each target against prog.targetCharset,
+// which must not be nil, and returns the targets that matched. A target
+// that doesn't match is a data-quality problem - stray control
+// characters, mixed scripts from a bad encoding conversion, and so on -
+// that would otherwise produce misleading distances. Under --strict the
+// first such target aborts with an error naming the offending value;
+// otherwise it is skipped and a warning naming it is written to stderr.
+func (prog *Prog) validateTargetCharset(targets []string) ([]string, error) {
+	valid := make([]string, 0, len(targets))
+
+	for _, t := range targets {
+		if prog.targetCharset.MatchString(t) {
+			valid = append(valid, t)
+			continue
+		}
+
+		if prog.strict {
+			return nil, fmt.Errorf(
+				"target %q does not match the "+paramNameTargetCharset+
+					" pattern %q",
+				t, prog.targetCharset.String())
+		}
+
+		fmt.Fprintf(os.Stderr,
+			"warning: skipping target %q: does not match the "+
+				paramNameTargetCharset+" pattern %q\n",
+			t, prog.targetCharset.String())
+	}
+
+	return valid, nil
+}
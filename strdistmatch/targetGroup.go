@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// targetGroupSep separates the members of a target group given as a single
+// argument, for instance "colour|color" to match against either spelling.
+const targetGroupSep = "|"
+
+// parseTargetGroup splits s on targetGroupSep into its member strings. A
+// plain target with no separator parses as a group of one, so ungrouped
+// targets behave exactly as before.
+func parseTargetGroup(s string) []string {
+	return strings.Split(s, targetGroupSep)
+}
+
+// findLikeFunc is the signature of a function that finds the population
+// words like a given string, as used by findLikeGroup - either
+// (*strdist.Finder).FindLike itself or a --use-trie variant of it.
+type findLikeFunc func(f *strdist.Finder, s string, pop []string) []strdist.StrDist
+
+// findLike calls f.FindLike directly, with the findLikeFunc signature, for
+// use as the default, non-trie member finder.
+func findLike(f *strdist.Finder, s string, pop []string) []strdist.StrDist {
+	return f.FindLike(s, pop...)
+}
+
+// findLikeGroup returns, for each population word matched by any member of
+// group within f's threshold, its distance to the nearest group member,
+// sorted by ascending distance. For a group of one this is exactly
+// find(f, group[0], pop).
+func findLikeGroup(
+	f *strdist.Finder, group, pop []string, find findLikeFunc,
+) []strdist.StrDist {
+	if len(group) == 1 {
+		return find(f, group[0], pop)
+	}
+
+	nearest := map[string]float64{}
+	order := []string{}
+
+	for _, member := range group {
+		for _, sd := range find(f, member, pop) {
+			d, seen := nearest[sd.Str]
+			if !seen {
+				order = append(order, sd.Str)
+			}
+
+			if !seen || sd.Dist < d {
+				nearest[sd.Str] = sd.Dist
+			}
+		}
+	}
+
+	sd := make([]strdist.StrDist, 0, len(order))
+	for _, s := range order {
+		sd = append(sd, strdist.StrDist{Str: s, Dist: nearest[s]})
+	}
+
+	sort.Slice(sd, func(i, j int) bool { return sd[i].Dist < sd[j].Dist })
+
+	return sd
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// terminalWidth returns the width of the terminal connected to stdout and
+// true, if stdout is a terminal. If stdout has been redirected to a file
+// or piped to another program it returns 0, false, so that callers can
+// leave their output unchanged for non-interactive use.
+func terminalWidth() (int, bool) {
+	fd := int(os.Stdout.Fd())
+	if !term.IsTerminal(fd) {
+		return 0, false
+	}
+
+	w, _, err := term.GetSize(fd)
+	if err != nil {
+		return 0, false
+	}
+
+	return w, true
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// thresholdRange gives the valid range, inclusive at both ends, for an
+// algorithm's --algo threshold subval.
+type thresholdRange struct {
+	min, max float64
+}
+
+// thresholdRanges gives the valid threshold range for every algorithm
+// this tool supports. The scaled algorithms (cosine, Jaccard, weighted
+// Jaccard, scaled Levenshtein) return a Dist that is already a fraction
+// in [0,1], so a threshold outside that range could never change which
+// matches pass; the raw edit-distance algorithms have no natural upper
+// bound but can never be satisfied by a negative one. Algorithm names not
+// listed here are left unchecked.
+var thresholdRanges = map[string]thresholdRange{
+	strdist.AlgoNameLevenshtein:       {0, math.Inf(1)},
+	AlgoNameDamerauLevenshtein:        {0, math.Inf(1)},
+	strdist.AlgoNameHamming:           {0, math.Inf(1)},
+	strdist.AlgoNameScaledLevenshtein: {0, 1},
+	strdist.AlgoNameCosine:            {0, 1},
+	strdist.AlgoNameJaccard:           {0, 1},
+	strdist.AlgoNameWeightedJaccard:   {0, 1},
+}
+
+// checkThreshold returns an error, naming algoName's valid range, if
+// threshold falls outside it. Algorithm names with no registered range
+// are left unchecked.
+func checkThreshold(algoName string, threshold float64) error {
+	r, ok := thresholdRanges[algoName]
+	if !ok {
+		return nil
+	}
+
+	if threshold < r.min || threshold > r.max {
+		return fmt.Errorf(
+			"threshold %g for %q is out of range: it must be in [%s, %s]",
+			threshold, algoName, formatBound(r.min), formatBound(r.max))
+	}
+
+	return nil
+}
+
+// formatBound formats one end of a thresholdRange, showing an infinite
+// upper bound as the infinity symbol rather than Go's "+Inf".
+func formatBound(b float64) string {
+	if math.IsInf(b, 1) {
+		return "∞"
+	}
+
+	return fmt.Sprintf("%g", b)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nickwells/col.mod/v4/col"
+	"github.com/nickwells/col.mod/v4/colfmt"
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// timingTracker accumulates the total time spent and the number of calls
+// made per finder, for --show-timing. It is written to concurrently, from
+// the goroutines searching each finder for a given target, so access is
+// guarded by a mutex.
+type timingTracker struct {
+	mu    sync.Mutex
+	total map[*strdist.Finder]time.Duration
+	calls map[*strdist.Finder]int
+}
+
+// newTimingTracker returns a new, empty timingTracker.
+func newTimingTracker() *timingTracker {
+	return &timingTracker{
+		total: map[*strdist.Finder]time.Duration{},
+		calls: map[*strdist.Finder]int{},
+	}
+}
+
+// add records that a call to f's FindLike took d.
+func (t *timingTracker) add(f *strdist.Finder, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.total[f] += d
+	t.calls[f]++
+}
+
+// timeFind wraps find so that every call is timed and recorded against f
+// in t, before returning find's result unchanged.
+func (t *timingTracker) timeFind(find findLikeFunc) findLikeFunc {
+	return func(f *strdist.Finder, s string, pop []string) []strdist.StrDist {
+		start := time.Now()
+		sd := find(f, s, pop)
+		t.add(f, time.Since(start))
+
+		return sd
+	}
+}
+
+// writeTimingReport prints a summary table of total and mean time spent in
+// each finder's FindLike, for --show-timing. It is printed after the main
+// results table so it never displaces a result column.
+//
+//nolint:mnd
+func (prog *Prog) writeTimingReport(finders []*strdist.Finder) {
+	maxAlgoNameLen := getMaxAlgoNameLen(finders)
+	if maxAlgoNameLen == 0 {
+		maxAlgoNameLen = 1
+	}
+
+	h, err := col.NewHeader()
+	if err != nil {
+		fmt.Printf("Couldn't make the timing report header: %s\n", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	algoCol := col.New(colfmt.String{W: maxAlgoNameLen}, "algorithm")
+	cols := []*col.Col{
+		col.New(colfmt.Int{W: 10}, "calls"),
+		col.New(&colfmt.Float{W: 12, Prec: 4}, "total", "seconds"),
+		col.New(&colfmt.Float{W: 12, Prec: 6}, "mean", "seconds"),
+	}
+
+	r, err := col.NewReport(h, prog.outputWriter, algoCol, cols...)
+	if err != nil {
+		fmt.Println("Couldn't create the timing report:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	fmt.Fprintln(prog.outputWriter)
+
+	for _, f := range finders {
+		calls := prog.timing.calls[f]
+		total := prog.timing.total[f]
+
+		mean := 0.0
+		if calls > 0 {
+			mean = total.Seconds() / float64(calls)
+		}
+
+		err := r.PrintRow(f.Algo.Name(), calls, total.Seconds(), mean)
+		if err != nil {
+			fmt.Printf("Cannot print the timing report: %s\n", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+	}
+}
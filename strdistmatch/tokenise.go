@@ -0,0 +1,84 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defaultTokenRegex is the pattern used to split a phrase into tokens for
+// --tokenise when --token-regex is not given: it splits on any run of
+// whitespace.
+var defaultTokenRegex = regexp.MustCompile(`\s+`)
+
+// tokenise splits s into tokens using re and rejoins them with a single
+// space, discarding any empty tokens. This lets phrases that differ only
+// in the delimiter used between their words - a hyphen or a slash rather
+// than whitespace, say - compare as equal once tokenised.
+func tokenise(re *regexp.Regexp, s string) string {
+	parts := re.Split(s, -1)
+
+	tokens := make([]string, 0, len(parts))
+
+	for _, p := range parts {
+		if p != "" {
+			tokens = append(tokens, p)
+		}
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// tokeniseGroup returns a copy of group with each member tokenised.
+func tokeniseGroup(re *regexp.Regexp, group []string) []string {
+	tGroup := make([]string, len(group))
+
+	for i, m := range group {
+		tGroup[i] = tokenise(re, m)
+	}
+
+	return tGroup
+}
+
+// tokenisePop returns a copy of pop with every word tokenised for
+// comparison purposes, along with a map from each tokenised form back to
+// the original word it came from. If two population words tokenise to the
+// same form the later one wins.
+func tokenisePop(re *regexp.Regexp, pop []string) ([]string, map[string]string) {
+	tPop := make([]string, len(pop))
+	origOf := make(map[string]string, len(pop))
+
+	for i, w := range pop {
+		t := tokenise(re, w)
+		tPop[i] = t
+		origOf[t] = w
+	}
+
+	return tPop, origOf
+}
+
+// tokeniseWords tokenises pop and returns the tokenised population along
+// with an origOf map from tokenised form back to the true original word,
+// composing with any origOf map already produced by an earlier
+// transformation (such as --transliterate) so the reported value is
+// always the untransformed original.
+func (prog *Prog) tokeniseWords(
+	pop []string, origOf map[string]string,
+) ([]string, map[string]string) {
+	tPop, tOrigOf := tokenisePop(prog.tokenRegex, pop)
+
+	if origOf == nil {
+		return tPop, tOrigOf
+	}
+
+	composed := make(map[string]string, len(tOrigOf))
+
+	for t, w := range tOrigOf {
+		if orig, ok := origOf[w]; ok {
+			composed[t] = orig
+		} else {
+			composed[t] = w
+		}
+	}
+
+	return tPop, composed
+}
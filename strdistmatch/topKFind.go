@@ -0,0 +1,91 @@
+package main
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// topKHeap is a bounded max-heap of strdist.StrDist, ordered so that its
+// worst (least similar, per strDistLess) match is always at the root. This
+// lets findLikeTopK evict the current worst match in O(log k) once it has
+// k candidates, rather than collecting and sorting every candidate in the
+// population.
+type topKHeap struct {
+	sd        []strdist.StrDist
+	targetLen int
+}
+
+func (h topKHeap) Len() int { return len(h.sd) }
+
+// Less reports whether i is worse than j, so that the worst candidate
+// bubbles up to the root (index 0) of the min-heap container/heap
+// maintains.
+func (h topKHeap) Less(i, j int) bool {
+	return strDistLess(h.sd[j], h.sd[i], h.targetLen)
+}
+
+func (h topKHeap) Swap(i, j int) { h.sd[i], h.sd[j] = h.sd[j], h.sd[i] }
+
+func (h *topKHeap) Push(x any) { h.sd = append(h.sd, x.(strdist.StrDist)) }
+
+func (h *topKHeap) Pop() any {
+	old := h.sd
+	n := len(old)
+	x := old[n-1]
+	h.sd = old[:n-1]
+
+	return x
+}
+
+// findLikeTopK behaves like f.FindLike(s, pop...) truncated to its k best
+// matches, except that it never holds more than k candidates in memory at
+// once: pop is scanned with a bounded max-heap of size k, evicting the
+// current worst candidate whenever a better one is found, rather than
+// collecting every match and sorting the lot. The returned order matches
+// FindLike's own best-first ordering, but the count of matches found is
+// capped at k too, since anything beyond the k best is discarded rather
+// than counted. It has to duplicate FindLike's preparation and filtering
+// logic, since a heap-bounded scan isn't something FindLike itself
+// supports.
+func findLikeTopK(f *strdist.Finder, s string, pop []string, k int) []strdist.StrDist {
+	if len(pop) == 0 || k <= 0 {
+		return nil
+	}
+
+	s = prepStr(f.FinderConfig, s)
+	if len(s) < f.FinderConfig.MinStrLength {
+		return nil
+	}
+
+	h := &topKHeap{targetLen: len(s)}
+
+	for _, pOrig := range pop {
+		p := prepStr(f.FinderConfig, pOrig)
+		if len(p) < f.FinderConfig.MinStrLength {
+			continue
+		}
+
+		d := f.Algo.Dist(s, p)
+		if d > f.FinderConfig.Threshold {
+			continue
+		}
+
+		sd := strdist.StrDist{Str: pOrig, Dist: d}
+
+		if h.Len() < k {
+			heap.Push(h, sd)
+			continue
+		}
+
+		if strDistLess(sd, h.sd[0], len(s)) {
+			h.sd[0] = sd
+			heap.Fix(h, 0)
+		}
+	}
+
+	sort.Slice(h.sd, minStrLenSortLess(h.sd, len(s)))
+
+	return h.sd
+}
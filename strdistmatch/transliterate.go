@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cyrillicTransliteration maps lower-cased Cyrillic letters to a Latin
+// approximation. It is deliberately limited to the one script rather
+// than attempting a complete mapping of every non-Latin script; runes
+// with no entry are left as they are by transliterate.
+var cyrillicTransliteration = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// transliterate converts s to a Latin approximation, so that, for
+// instance, "Москва" becomes "Moskva". Runes from scripts with no
+// entry in the transliteration table - including plain Latin text -
+// are passed through unchanged.
+func transliterate(s string) string {
+	var b strings.Builder
+
+	for _, r := range s {
+		repl, ok := cyrillicTransliteration[unicode.ToLower(r)]
+		if !ok {
+			b.WriteRune(r)
+			continue
+		}
+
+		if unicode.IsUpper(r) && repl != "" {
+			repl = strings.ToUpper(repl[:1]) + repl[1:]
+		}
+
+		b.WriteString(repl)
+	}
+
+	return b.String()
+}
+
+// transliterateGroup returns a copy of group with each member transliterated.
+func transliterateGroup(group []string) []string {
+	tGroup := make([]string, len(group))
+
+	for i, m := range group {
+		tGroup[i] = transliterate(m)
+	}
+
+	return tGroup
+}
+
+// transliteratePop returns a copy of pop with every word transliterated
+// for comparison purposes, along with a map from each transliterated
+// form back to the original word it came from. If two population words
+// transliterate to the same form the later one wins.
+func transliteratePop(pop []string) ([]string, map[string]string) {
+	tPop := make([]string, len(pop))
+	origOf := make(map[string]string, len(pop))
+
+	for i, w := range pop {
+		t := transliterate(w)
+		tPop[i] = t
+		origOf[t] = w
+	}
+
+	return tPop, origOf
+}
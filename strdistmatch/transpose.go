@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nickwells/col.mod/v4/col"
+	"github.com/nickwells/col.mod/v4/colfmt"
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// maxTransposeTargets is the largest number of targets --transpose will
+// lay out as columns; beyond this the table stops reading better than the
+// normal, target-per-row layout.
+const maxTransposeTargets = 8
+
+// writeTransposedReport prints one row per finder, with a target per
+// column instead of the usual layout of a target per row and a finder per
+// block. Each cell holds the best match for that finder/target pair as
+// "distance: value", or is left empty where there was no match. It's for
+// --transpose, which only makes sense with a handful of targets.
+func (prog *Prog) writeTransposedReport(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	if len(targets) > maxTransposeTargets {
+		fmt.Printf(
+			"--transpose only makes sense for up to %d targets, got %d\n",
+			maxTransposeTargets, len(targets))
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	cells, maxCellLen := prog.transposeCells(finders, searchPop, targets, origOf, find)
+
+	maxAlgoNameLen := getMaxAlgoNameLen(finders)
+	if maxAlgoNameLen == 0 {
+		maxAlgoNameLen = 1
+	}
+
+	h, err := prog.newReportHeader()
+	if err != nil {
+		fmt.Printf("Couldn't make the report header: %s\n", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	algoCol := col.New(colfmt.String{W: maxAlgoNameLen}, "algorithm")
+
+	cols := make([]*col.Col, len(targets))
+	for i, target := range targets {
+		w := maxCellLen[i]
+		if w == 0 {
+			w = 1
+		}
+
+		cols[i] = col.New(
+			withEmptyCell(colfmt.String{W: w, IgnoreNil: true}, prog.emptyCell),
+			target)
+	}
+
+	r, err := col.NewReport(h, prog.outputWriter, algoCol, cols...)
+	if err != nil {
+		fmt.Println("Couldn't create the report:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	for i, f := range finders {
+		vals := []any{f.Algo.Name()}
+
+		for _, cell := range cells[i] {
+			if cell == "" {
+				vals = append(vals, nil)
+			} else {
+				vals = append(vals, cell)
+			}
+		}
+
+		if err := r.PrintRow(vals...); err != nil {
+			fmt.Println("Couldn't print the report row:", err)
+			prog.SetExitStatus(1)
+
+			return
+		}
+	}
+}
+
+// transposeCells computes the "distance: value" cell for every
+// finder/target pair, along with the widest cell in each target's column
+// so the report's columns can be sized before any row is printed.
+func (prog *Prog) transposeCells(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) ([][]string, []uint) {
+	cells := make([][]string, len(finders))
+	maxCellLen := make([]uint, len(targets))
+
+	for fi, f := range finders {
+		cells[fi] = make([]string, len(targets))
+
+		for ti, target := range targets {
+			group := parseTargetGroup(target)
+			if prog.transliterate {
+				group = transliterateGroup(group)
+			}
+
+			if prog.tokenise {
+				group = tokeniseGroup(prog.tokenRegex, group)
+			}
+
+			if prog.graphemeClusters {
+				group = prog.graphemeEnc.encodeGroup(group)
+			}
+
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, origOf)
+			}
+
+			cell := ""
+			if len(sd) > 0 {
+				val := sd[0].Str
+				if orig, ok := origOf[val]; ok {
+					val = orig
+				}
+
+				cell = fmt.Sprintf("%g: %s", sd[0].Dist, val)
+			}
+
+			cells[fi][ti] = cell
+			if l := uint(len([]rune(cell))); l > maxCellLen[ti] { //nolint:gosec
+				maxCellLen[ti] = l
+			}
+		}
+	}
+
+	return cells, maxCellLen
+}
@@ -0,0 +1,117 @@
+package main
+
+// trieNode is a node in a trie built over the population, used by --use-trie
+// to narrow the candidates scored for a target down to those within a
+// bounded edit distance, rather than scoring the whole population.
+type trieNode struct {
+	children map[rune]*trieNode
+	// words holds the original population words whose prepared form ends
+	// at this node. It is usually at most one entry but several original
+	// words can share a prepared form, for instance under --to-lower.
+	words []string
+}
+
+func newTrieNode() *trieNode {
+	return &trieNode{children: map[rune]*trieNode{}}
+}
+
+// trie is a trie over the prepared forms of a population, together with
+// the longest prepared word length, which is needed to bound the edit
+// budget for algorithms whose threshold isn't itself an edit count.
+type trie struct {
+	root       *trieNode
+	maxWordLen int
+}
+
+// buildTrie constructs a trie from pop, indexed by each word's prepared
+// form (as returned by prep), storing the original word at the node the
+// prepared form ends at.
+func buildTrie(pop []string, prep func(string) string) *trie {
+	t := &trie{root: newTrieNode()}
+
+	for _, orig := range pop {
+		p := prep(orig)
+
+		n := t.root
+		wordLen := 0
+
+		for _, r := range p {
+			wordLen++
+
+			child, ok := n.children[r]
+			if !ok {
+				child = newTrieNode()
+				n.children[r] = child
+			}
+
+			n = child
+		}
+
+		if wordLen > t.maxWordLen {
+			t.maxWordLen = wordLen
+		}
+
+		n.words = append(n.words, orig)
+	}
+
+	return t
+}
+
+// candidatesWithinEditDistance walks the trie, keeping one Levenshtein row
+// per node (the standard trie/Levenshtein-automaton approach), and returns
+// every original word whose prepared form is within budget edits of
+// target. It only descends into branches which could still produce such a
+// word, which is what makes it cheaper than scoring every population word.
+func (t *trie) candidatesWithinEditDistance(target string, budget int) []string {
+	targetRunes := []rune(target)
+
+	firstRow := make([]int, len(targetRunes)+1)
+	for i := range firstRow {
+		firstRow[i] = i
+	}
+
+	candidates := []string{}
+
+	var walk func(n *trieNode, r rune, prevRow []int)
+
+	walk = func(n *trieNode, r rune, prevRow []int) {
+		row := make([]int, len(prevRow))
+		row[0] = prevRow[0] + 1
+
+		for i := 1; i < len(row); i++ {
+			subsCost := 1
+			if targetRunes[i-1] == r {
+				subsCost = 0
+			}
+
+			del := row[i-1] + 1
+			ins := prevRow[i] + 1
+			sub := prevRow[i-1] + subsCost
+
+			row[i] = min(del, min(ins, sub))
+		}
+
+		if len(n.words) > 0 && row[len(row)-1] <= budget {
+			candidates = append(candidates, n.words...)
+		}
+
+		minInRow := row[0]
+		for _, v := range row[1:] {
+			minInRow = min(minInRow, v)
+		}
+
+		if minInRow > budget {
+			return
+		}
+
+		for cr, child := range n.children {
+			walk(child, cr, row)
+		}
+	}
+
+	for r, child := range t.root.children {
+		walk(child, r, firstRow)
+	}
+
+	return candidates
+}
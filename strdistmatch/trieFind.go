@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"strings"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// prepStr replicates FinderConfig's own (unexported) string preparation, so
+// that a target can be measured against the trie using exactly the same
+// case-folding and rune-stripping that the Finder itself will apply.
+func prepStr(fc strdist.FinderConfig, s string) string {
+	if fc.MapToLowerCase {
+		s = strings.ToLower(s)
+	}
+
+	if fc.StripRunes != "" {
+		stripped := make([]rune, 0, len(s))
+
+		for _, r := range s {
+			if strings.ContainsRune(fc.StripRunes, r) {
+				continue
+			}
+
+			stripped = append(stripped, r)
+		}
+
+		s = string(stripped)
+	}
+
+	return s
+}
+
+// trieEditBudget returns the maximum edit distance within which a
+// population word could still fall within f's threshold, and true, if f's
+// algorithm is one for which that bound holds. Levenshtein and Hamming
+// thresholds are edit counts already; scaled Levenshtein's threshold is a
+// fraction of the longer string's length, so it is scaled up by the
+// longest length involved. Any other algorithm, Damerau-Levenshtein and
+// the n-gram set based ones, is not handled here, so ok is false and
+// --use-trie does not narrow its candidates.
+func trieEditBudget(f *strdist.Finder, targetLen, maxPopWordLen int) (
+	budget int, ok bool,
+) {
+	switch f.Algo.Name() {
+	case strdist.AlgoNameLevenshtein, strdist.AlgoNameHamming:
+		return int(f.FinderConfig.Threshold), true
+	case strdist.AlgoNameScaledLevenshtein:
+		maxLen := max(targetLen, maxPopWordLen)
+		return int(math.Ceil(f.FinderConfig.Threshold * float64(maxLen))), true
+	default:
+		return 0, false
+	}
+}
+
+// findLikeTrie behaves exactly like f.FindLike(s, pop...) but, when f's
+// algorithm is edit-distance bounded, first narrows pop down using t to
+// only those words within a computed edit-distance budget of s, scoring
+// just those candidates instead of the whole population. For any other
+// algorithm it scores pop directly, since there is no bound that would let
+// it prune without risking a missed match.
+func findLikeTrie(
+	f *strdist.Finder, t *trie, s string, pop []string,
+) []strdist.StrDist {
+	prepped := prepStr(f.FinderConfig, s)
+
+	budget, ok := trieEditBudget(f, len([]rune(prepped)), t.maxWordLen)
+	if !ok {
+		return f.FindLike(s, pop...)
+	}
+
+	candidates := t.candidatesWithinEditDistance(prepped, budget)
+
+	return f.FindLike(s, candidates...)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestBuildTrieAndCandidatesWithinEditDistance(t *testing.T) {
+	pop := []string{"kitten", "sitting", "bitten", "mitten", "kitchen", "cat"}
+
+	trie := buildTrie(pop, func(s string) string { return s })
+
+	const wantMaxWordLen = 7 // "sitting" and "kitchen"
+	if trie.maxWordLen != wantMaxWordLen {
+		t.Fatalf("maxWordLen == %d, want %d", trie.maxWordLen, wantMaxWordLen)
+	}
+
+	testCases := []struct {
+		name    string
+		target  string
+		budget  int
+		expWord []string
+	}{
+		{
+			name:    "exact match only, budget 0",
+			target:  "kitten",
+			budget:  0,
+			expWord: []string{"kitten"},
+		},
+		{
+			name:    "budget 1 pulls in one-edit neighbours",
+			target:  "kitten",
+			budget:  1,
+			expWord: []string{"bitten", "kitten", "mitten"},
+		},
+		{
+			name:    "budget 3 matches the classic kitten/sitting distance",
+			target:  "kitten",
+			budget:  3,
+			expWord: []string{"bitten", "kitchen", "kitten", "mitten", "sitting"},
+		},
+		{
+			name:    "no candidate within budget",
+			target:  "zzzzzz",
+			budget:  1,
+			expWord: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		got := trie.candidatesWithinEditDistance(tc.target, tc.budget)
+		sort.Strings(got)
+
+		if len(got) != len(tc.expWord) {
+			t.Fatalf("%s: candidatesWithinEditDistance(%q, %d) == %q, want %q",
+				tc.name, tc.target, tc.budget, got, tc.expWord)
+		}
+
+		for i, w := range got {
+			if w != tc.expWord[i] {
+				t.Errorf("%s: candidatesWithinEditDistance(%q, %d)[%d] == %q,"+
+					" want %q",
+					tc.name, tc.target, tc.budget, i, w, tc.expWord[i])
+			}
+		}
+	}
+}
+
+func TestBuildTrieUsesPreparedForm(t *testing.T) {
+	pop := []string{"Kitten", "KITTEN", "cat"}
+
+	trie := buildTrie(pop, func(s string) string {
+		lower := make([]rune, 0, len(s))
+		for _, r := range s {
+			if r >= 'A' && r <= 'Z' {
+				r += 'a' - 'A'
+			}
+
+			lower = append(lower, r)
+		}
+
+		return string(lower)
+	})
+
+	got := trie.candidatesWithinEditDistance("kitten", 0)
+	sort.Strings(got)
+
+	want := []string{"KITTEN", "Kitten"}
+	if len(got) != len(want) {
+		t.Fatalf("candidatesWithinEditDistance(%q, 0) == %q, want %q",
+			"kitten", got, want)
+	}
+
+	for i, w := range got {
+		if w != want[i] {
+			t.Errorf("candidatesWithinEditDistance(%q, 0)[%d] == %q, want %q",
+				"kitten", i, w, want[i])
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import "strings"
+
+// trimPopulation applies strings.TrimSpace to every entry in pop, dropping
+// any that become empty, and returns the result along with the number of
+// entries dropped.
+func trimPopulation(pop []string) ([]string, int) {
+	trimmed := make([]string, 0, len(pop))
+
+	for _, w := range pop {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+
+		trimmed = append(trimmed, w)
+	}
+
+	return trimmed, len(pop) - len(trimmed)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// reportWhy prints, for each finder, a line explaining precisely why word
+// does or doesn't match target under that finder: the effective
+// (preprocessed) forms of both strings, whether either was excluded by
+// MinStrLength (respecting minStrLenModes' minStrLenAppliesTo setting for
+// that finder), the computed distance and the finder's threshold, and
+// whether that distance falls within the threshold. It is the --why-target
+// / --why-word diagnostic, for the common case of "I expected these two
+// strings to match and they didn't, why not?".
+func reportWhy(
+	finders []*strdist.Finder, target, word string,
+	minStrLenModes map[*strdist.Finder]string,
+) {
+	for _, f := range finders {
+		fmt.Printf("%s (%s):\n", f.Algo.Name(), f.Algo.Desc())
+
+		prepTarget := prepStr(f.FinderConfig, target)
+		prepWord := prepStr(f.FinderConfig, word)
+
+		fmt.Printf("\ttarget: %q -> %q\n", target, prepTarget)
+		fmt.Printf("\tword:   %q -> %q\n", word, prepWord)
+
+		mode := minStrLenModes[f]
+
+		if mode != minStrLenApplyPopulation &&
+			len(prepTarget) < f.FinderConfig.MinStrLength {
+			fmt.Printf(
+				"\tno match: the target is shorter than %s (%d), which"+
+					" applies to %s\n",
+				paramNameMinStrLen, f.FinderConfig.MinStrLength, mode)
+
+			continue
+		}
+
+		if mode != minStrLenApplyTarget &&
+			len(prepWord) < f.FinderConfig.MinStrLength {
+			fmt.Printf(
+				"\tno match: the word is shorter than %s (%d), which"+
+					" applies to %s\n",
+				paramNameMinStrLen, f.FinderConfig.MinStrLength, mode)
+
+			continue
+		}
+
+		dist := f.Algo.Dist(prepTarget, prepWord)
+
+		fmt.Printf("\tdistance: %.4f, threshold: %.4f\n",
+			dist, f.FinderConfig.Threshold)
+
+		if dist > f.FinderConfig.Threshold {
+			fmt.Printf("\tno match: the distance exceeds the threshold\n")
+		} else {
+			fmt.Printf("\tmatch: the distance is within the threshold\n")
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// writeOutputCSV writes one row per (target, finder) combination to
+// stdout, using encoding/csv: target, algorithm, threshold, min-str-len,
+// the result count, then a distance/value pair per match up to
+// prog.maxResults. A target/finder pair with fewer than prog.maxResults
+// matches leaves the remaining distance/value fields empty, so every row
+// has the same number of columns. origOf, if non-nil, maps a (possibly
+// transliterated) population value back to the original word to report.
+func (prog *Prog) writeOutputCSV(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	w := csv.NewWriter(os.Stdout)
+
+	header := []string{
+		"target", "algorithm", "threshold", "min-str-len", "count",
+	}
+	for i := 1; i <= prog.maxResults; i++ {
+		header = append(header,
+			fmt.Sprintf("distance-%d", i), fmt.Sprintf("value-%d", i))
+	}
+
+	if err := w.Write(header); err != nil {
+		fmt.Println("Couldn't write the CSV results:", err)
+		prog.SetExitStatus(1)
+
+		return
+	}
+
+	for _, target := range targets {
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		for _, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, origOf)
+			}
+
+			row := []string{
+				target,
+				f.Algo.Name(),
+				strconv.FormatFloat(f.FinderConfig.Threshold, 'g', -1, 64),
+				strconv.Itoa(f.FinderConfig.MinStrLength),
+				strconv.Itoa(len(sd)),
+			}
+
+			for i := 0; i < prog.maxResults; i++ {
+				if i >= len(sd) {
+					row = append(row, "", "")
+					continue
+				}
+
+				val := sd[i].Str
+				if orig, ok := origOf[val]; ok {
+					val = orig
+				}
+
+				row = append(row,
+					strconv.FormatFloat(sd[i].Dist, 'g', -1, 64), val)
+			}
+
+			if err := w.Write(row); err != nil {
+				fmt.Println("Couldn't write the CSV results:", err)
+				prog.SetExitStatus(1)
+
+				return
+			}
+		}
+	}
+
+	w.Flush()
+
+	if err := w.Error(); err != nil {
+		fmt.Println("Couldn't write the CSV results:", err)
+		prog.SetExitStatus(1)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// outputJSONFinder is one finder's entry in an --output-format=json
+// result: its algorithm name and threshold, and its matches for the
+// target, best first, up to prog.maxResults.
+type outputJSONFinder struct {
+	Algorithm string    `json:"algorithm"`
+	Threshold float64   `json:"threshold"`
+	Matches   []jsonHit `json:"matches"`
+}
+
+// outputJSONResult is one target's entry in an --output-format=json
+// report.
+type outputJSONResult struct {
+	Target  string             `json:"target"`
+	Finders []outputJSONFinder `json:"finders"`
+}
+
+// writeOutputJSON writes the results of searching for each of targets
+// against searchPop, using the given finders, as a JSON array to stdout,
+// bypassing col.Report entirely. origOf, if non-nil, maps a (possibly
+// transliterated) population value back to the original word to report.
+func (prog *Prog) writeOutputJSON(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	results := make([]outputJSONResult, 0, len(targets))
+
+	for _, target := range targets {
+		res := outputJSONResult{
+			Target:  target,
+			Finders: make([]outputJSONFinder, 0, len(finders)),
+		}
+
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		for _, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, origOf)
+			}
+
+			matches := make([]jsonHit, 0, min(len(sd), prog.maxResults))
+
+			for i := range prog.maxResults {
+				if i >= len(sd) {
+					break
+				}
+
+				val := sd[i].Str
+				if orig, ok := origOf[val]; ok {
+					val = orig
+				}
+
+				matches = append(matches,
+					jsonHit{Value: val, Distance: sd[i].Dist})
+			}
+
+			res.Finders = append(res.Finders, outputJSONFinder{
+				Algorithm: f.Algo.Name(),
+				Threshold: f.FinderConfig.Threshold,
+				Matches:   matches,
+			})
+		}
+
+		results = append(results, res)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(results); err != nil {
+		fmt.Println("Couldn't write the JSON results:", err)
+		prog.SetExitStatus(1)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nickwells/strdist.mod/v2/strdist"
+)
+
+// mdEscape escapes the pipe characters in s so that it can't be mistaken
+// for a column separator when pasted into a GitHub-flavored Markdown
+// table.
+func mdEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// writeMarkdownRow writes vals as a GitHub-flavored Markdown table row,
+// escaping pipe characters in each value.
+func writeMarkdownRow(vals []string) {
+	escaped := make([]string, len(vals))
+	for i, v := range vals {
+		escaped[i] = mdEscape(v)
+	}
+
+	fmt.Println("| " + strings.Join(escaped, " | ") + " |")
+}
+
+// writeOutputMarkdown writes one row per (target, finder) combination to
+// stdout as a GitHub-flavored Markdown table, with the same logical
+// columns as writeOutputCSV: target, algorithm, threshold, min-str-len,
+// the result count, then a distance/value pair per match up to
+// prog.maxResults. A target/finder pair with fewer than prog.maxResults
+// matches leaves the remaining distance/value cells empty, so every row
+// has the same number of columns. The header separator row (|---|) is
+// emitted once, straight after the header. origOf, if non-nil, maps a
+// (possibly transliterated) population value back to the original word
+// to report.
+func (prog *Prog) writeOutputMarkdown(
+	finders []*strdist.Finder, searchPop, targets []string,
+	origOf map[string]string, find findLikeFunc,
+) {
+	header := []string{
+		"target", "algorithm", "threshold", "min-str-len", "count",
+	}
+	for i := 1; i <= prog.maxResults; i++ {
+		header = append(header,
+			fmt.Sprintf("distance-%d", i), fmt.Sprintf("value-%d", i))
+	}
+
+	writeMarkdownRow(header)
+
+	sep := make([]string, len(header))
+	for i := range sep {
+		sep[i] = "---"
+	}
+
+	writeMarkdownRow(sep)
+
+	for _, target := range targets {
+		group := parseTargetGroup(target)
+		if prog.transliterate {
+			group = transliterateGroup(group)
+		}
+
+		if prog.tokenise {
+			group = tokeniseGroup(prog.tokenRegex, group)
+		}
+
+		if prog.graphemeClusters {
+			group = prog.graphemeEnc.encodeGroup(group)
+		}
+
+		for _, f := range finders {
+			sd := prog.filterMinDistance(findLikeGroup(f, group, searchPop, find))
+
+			if prog.preferFrequent {
+				prog.sortByFrequency(sd, origOf)
+			}
+
+			row := []string{
+				target,
+				f.Algo.Name(),
+				strconv.FormatFloat(f.FinderConfig.Threshold, 'g', -1, 64),
+				strconv.Itoa(f.FinderConfig.MinStrLength),
+				strconv.Itoa(len(sd)),
+			}
+
+			for i := 0; i < prog.maxResults; i++ {
+				if i >= len(sd) {
+					row = append(row, "", "")
+					continue
+				}
+
+				val := sd[i].Str
+				if orig, ok := origOf[val]; ok {
+					val = orig
+				}
+
+				row = append(row,
+					strconv.FormatFloat(sd[i].Dist, 'g', -1, 64), val)
+			}
+
+			writeMarkdownRow(row)
+		}
+	}
+}